@@ -8,9 +8,14 @@ import (
 
 	"github.com/MagicNetLab/go-diploma/internal/config"
 	"github.com/MagicNetLab/go-diploma/internal/services/accrual"
+	"github.com/MagicNetLab/go-diploma/internal/services/crash"
+	"github.com/MagicNetLab/go-diploma/internal/services/keyring"
 	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/notifier"
+	"github.com/MagicNetLab/go-diploma/internal/services/oidc"
 	"github.com/MagicNetLab/go-diploma/internal/services/server"
 	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/MagicNetLab/go-diploma/internal/services/user"
 )
 
 func main() {
@@ -32,12 +37,47 @@ func initApp() {
 		return
 	}
 
+	err = crash.Init(cnf)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Fatal("failed initializing crash reports", args)
+		return
+	}
+
 	err = store.Init(cnf)
 	if err != nil {
 		args := map[string]interface{}{"error": err.Error()}
 		logger.Fatal("failed initializing store", args)
 		return
 	}
+
+	err = user.SyncNormalizedLogins()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Fatal("failed normalizing user logins", args)
+		return
+	}
+
+	err = keyring.Init(cnf)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Fatal("failed loading jwt keys", args)
+		return
+	}
+
+	err = notifier.Init(cnf)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Fatal("failed initializing notifier", args)
+		return
+	}
+
+	err = oidc.Init(cnf)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Fatal("failed initializing oidc provider", args)
+		return
+	}
 }
 
 func runServer() {
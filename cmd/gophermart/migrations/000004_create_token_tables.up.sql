@@ -0,0 +1,22 @@
+CREATE TABLE refresh_tokens
+(
+    id BIGSERIAL,
+    user_id BIGINT NOT NULL,
+    session_id VARCHAR NOT NULL,
+    token_hash VARCHAR NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS refresh_tokens_hash_idx ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS refresh_tokens_session_idx ON refresh_tokens (session_id);
+
+CREATE TABLE revoked_tokens
+(
+    jti VARCHAR NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS revoked_tokens_jti_idx ON revoked_tokens (jti);
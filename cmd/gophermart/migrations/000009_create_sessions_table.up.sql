@@ -0,0 +1,19 @@
+CREATE TABLE sessions
+(
+    id VARCHAR NOT NULL,
+    user_id BIGINT NOT NULL,
+    ip VARCHAR NOT NULL DEFAULT '',
+    user_agent VARCHAR NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    last_seen TIMESTAMP NOT NULL DEFAULT NOW(),
+    revoked_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS sessions_id_idx ON sessions (id);
+CREATE INDEX IF NOT EXISTS sessions_user_idx ON sessions (user_id);
+
+INSERT INTO sessions (id, user_id, created_at, last_seen)
+SELECT session_id, user_id, MIN(created_at), MAX(created_at)
+FROM refresh_tokens
+WHERE revoked_at IS NULL AND expires_at > NOW()
+GROUP BY session_id, user_id;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS balance_adjustments;
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE api_keys
+(
+    id BIGSERIAL,
+    user_id BIGINT NOT NULL,
+    name VARCHAR NOT NULL,
+    key_prefix VARCHAR NOT NULL,
+    key_hash VARCHAR NOT NULL,
+    scopes TEXT[] NOT NULL,
+    created_by BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP,
+    revoked_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS api_keys_id_idx ON api_keys (id);
+CREATE UNIQUE INDEX IF NOT EXISTS api_keys_hash_idx ON api_keys (key_hash);
+CREATE INDEX IF NOT EXISTS api_keys_user_idx ON api_keys (user_id);
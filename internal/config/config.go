@@ -20,7 +20,14 @@ func GetAppConfig() (AppEnvironment, error) {
 	getEnvValues()
 	getFlagsValues()
 
-	if conf.GetJWTSecret() == "" {
+	if conf.GetJWTSecret() == "" && conf.GetJWTKeysFile() == "" {
+		// A random secret logs everybody out on restart and is not shared
+		// between replicas, so it is only acceptable for development.
+		if conf.GetAppMode() != AppModeDevelopment {
+			return nil, errors.New("invalid config: JWT secret or keys file must be configured")
+		}
+
+		logger.Error("JWT secret and keys file are not configured: tokens will not survive a restart", nil)
 		err := conf.SetJWTSecret(getRandomSecret())
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error()}
@@ -74,6 +81,390 @@ func getEnvValues() {
 			logger.Error("fail set JWTSecret from env params: %v", args)
 		}
 	}
+
+	if envValues.HasLoginMaxAttempts() {
+		err = conf.SetLoginMaxAttempts(envValues.GetLoginMaxAttempts())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set LoginMaxAttempts from env params", args)
+		}
+	}
+
+	if envValues.HasLoginIPMaxAttempts() {
+		err = conf.SetLoginIPMaxAttempts(envValues.GetLoginIPMaxAttempts())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set LoginIPMaxAttempts from env params", args)
+		}
+	}
+
+	if envValues.HasLoginLockoutDuration() {
+		err = conf.SetLoginLockoutDuration(envValues.GetLoginLockoutDuration())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set LoginLockoutDuration from env params", args)
+		}
+	}
+
+	if envValues.HasAdminLogins() {
+		err = conf.SetAdminLogins(envValues.GetAdminLogins())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set AdminLogins from env params", args)
+		}
+	}
+
+	if envValues.HasAuthTokenPrecedence() {
+		err = conf.SetAuthTokenPrecedence(envValues.GetAuthTokenPrecedence())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set AuthTokenPrecedence from env params", args)
+		}
+	}
+
+	if envValues.HasJWTKeysFile() {
+		err = conf.SetJWTKeysFile(envValues.GetJWTKeysFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set JWTKeysFile from env params", args)
+		}
+	}
+
+	if envValues.HasPasswordHasher() {
+		err = conf.SetPasswordHasher(envValues.GetPasswordHasher())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set PasswordHasher from env params", args)
+		}
+	}
+
+	if envValues.HasBcryptCost() {
+		err = conf.SetBcryptCost(envValues.GetBcryptCost())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set BcryptCost from env params", args)
+		}
+	}
+
+	if envValues.HasArgon2Memory() {
+		err = conf.SetArgon2Memory(envValues.GetArgon2Memory())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set Argon2Memory from env params", args)
+		}
+	}
+
+	if envValues.HasArgon2Iterations() {
+		err = conf.SetArgon2Iterations(envValues.GetArgon2Iterations())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set Argon2Iterations from env params", args)
+		}
+	}
+
+	if envValues.HasArgon2Parallelism() {
+		err = conf.SetArgon2Parallelism(envValues.GetArgon2Parallelism())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set Argon2Parallelism from env params", args)
+		}
+	}
+
+	if envValues.HasPasswordMinLength() {
+		err = conf.SetPasswordMinLength(envValues.GetPasswordMinLength())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set PasswordMinLength from env params", args)
+		}
+	}
+
+	if envValues.HasPasswordMinClasses() {
+		err = conf.SetPasswordMinClasses(envValues.GetPasswordMinClasses())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set PasswordMinClasses from env params", args)
+		}
+	}
+
+	if envValues.HasNotifier() {
+		err = conf.SetNotifier(envValues.GetNotifier())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set Notifier from env params", args)
+		}
+	}
+
+	if envValues.HasNotifierFile() {
+		err = conf.SetNotifierFile(envValues.GetNotifierFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set NotifierFile from env params", args)
+		}
+	}
+
+	if envValues.HasSMTPHost() {
+		err = conf.SetSMTPHost(envValues.GetSMTPHost())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SMTPHost from env params", args)
+		}
+	}
+
+	if envValues.HasSMTPPort() {
+		err = conf.SetSMTPPort(envValues.GetSMTPPort())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SMTPPort from env params", args)
+		}
+	}
+
+	if envValues.HasSMTPUsername() {
+		err = conf.SetSMTPUsername(envValues.GetSMTPUsername())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SMTPUsername from env params", args)
+		}
+	}
+
+	if envValues.HasSMTPPassword() {
+		err = conf.SetSMTPPassword(envValues.GetSMTPPassword())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SMTPPassword from env params", args)
+		}
+	}
+
+	if envValues.HasSMTPFrom() {
+		err = conf.SetSMTPFrom(envValues.GetSMTPFrom())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SMTPFrom from env params", args)
+		}
+	}
+
+	if envValues.HasPasswordResetTTL() {
+		err = conf.SetPasswordResetTTL(envValues.GetPasswordResetTTL())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set PasswordResetTTL from env params", args)
+		}
+	}
+
+	if envValues.HasAdjustmentApprovalThreshold() {
+		err = conf.SetAdjustmentApprovalThreshold(envValues.GetAdjustmentApprovalThreshold())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set AdjustmentApprovalThreshold from env params", args)
+		}
+	}
+
+	if envValues.HasOIDCIssuer() {
+		err = conf.SetOIDCIssuer(envValues.GetOIDCIssuer())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCIssuer from env params", args)
+		}
+	}
+
+	if envValues.HasOIDCClientID() {
+		err = conf.SetOIDCClientID(envValues.GetOIDCClientID())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCClientID from env params", args)
+		}
+	}
+
+	if envValues.HasOIDCClientSecret() {
+		err = conf.SetOIDCClientSecret(envValues.GetOIDCClientSecret())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCClientSecret from env params", args)
+		}
+	}
+
+	if envValues.HasOIDCRedirectURL() {
+		err = conf.SetOIDCRedirectURL(envValues.GetOIDCRedirectURL())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCRedirectURL from env params", args)
+		}
+	}
+
+	if envValues.HasOIDCRoleClaim() {
+		err = conf.SetOIDCRoleClaim(envValues.GetOIDCRoleClaim())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCRoleClaim from env params", args)
+		}
+	}
+
+	if envValues.HasOIDCRoleMapping() {
+		err = conf.SetOIDCRoleMapping(envValues.GetOIDCRoleMapping())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCRoleMapping from env params", args)
+		}
+	}
+
+	if envValues.HasCookieSecure() {
+		err = conf.SetCookieSecure(envValues.GetCookieSecure())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CookieSecure from env params", args)
+		}
+	}
+
+	if envValues.HasCookieSameSite() {
+		err = conf.SetCookieSameSite(envValues.GetCookieSameSite())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CookieSameSite from env params", args)
+		}
+	}
+
+	if envValues.HasCookieDomain() {
+		err = conf.SetCookieDomain(envValues.GetCookieDomain())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CookieDomain from env params", args)
+		}
+	}
+
+	if envValues.HasSessionRenewalThreshold() {
+		err = conf.SetSessionRenewalThreshold(envValues.GetSessionRenewalThreshold())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SessionRenewalThreshold from env params", args)
+		}
+	}
+
+	if envValues.HasSessionMaxLifetime() {
+		err = conf.SetSessionMaxLifetime(envValues.GetSessionMaxLifetime())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SessionMaxLifetime from env params", args)
+		}
+	}
+
+	if envValues.HasTLSCertFile() {
+		err = conf.SetTLSCertFile(envValues.GetTLSCertFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set TLSCertFile from env params", args)
+		}
+	}
+
+	if envValues.HasTLSKeyFile() {
+		err = conf.SetTLSKeyFile(envValues.GetTLSKeyFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set TLSKeyFile from env params", args)
+		}
+	}
+
+	if envValues.HasTLSClientCAFile() {
+		err = conf.SetTLSClientCAFile(envValues.GetTLSClientCAFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set TLSClientCAFile from env params", args)
+		}
+	}
+
+	if envValues.HasTLSMinVersion() {
+		err = conf.SetTLSMinVersion(envValues.GetTLSMinVersion())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set TLSMinVersion from env params", args)
+		}
+	}
+
+	if envValues.HasTLSCipherSuites() {
+		err = conf.SetTLSCipherSuites(envValues.GetTLSCipherSuites())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set TLSCipherSuites from env params", args)
+		}
+	}
+
+	if envValues.HasServerReadHeaderTimeout() {
+		err = conf.SetServerReadHeaderTimeout(envValues.GetServerReadHeaderTimeout())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set ServerReadHeaderTimeout from env params", args)
+		}
+	}
+
+	if envValues.HasServerReadTimeout() {
+		err = conf.SetServerReadTimeout(envValues.GetServerReadTimeout())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set ServerReadTimeout from env params", args)
+		}
+	}
+
+	if envValues.HasServerWriteTimeout() {
+		err = conf.SetServerWriteTimeout(envValues.GetServerWriteTimeout())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set ServerWriteTimeout from env params", args)
+		}
+	}
+
+	if envValues.HasServerIdleTimeout() {
+		err = conf.SetServerIdleTimeout(envValues.GetServerIdleTimeout())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set ServerIdleTimeout from env params", args)
+		}
+	}
+
+	if envValues.HasMaxRequestBodySize() {
+		err = conf.SetMaxRequestBodySize(envValues.GetMaxRequestBodySize())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set MaxRequestBodySize from env params", args)
+		}
+	}
+
+	if envValues.HasCrashReportSink() {
+		err = conf.SetCrashReportSink(envValues.GetCrashReportSink())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CrashReportSink from env params", args)
+		}
+	}
+
+	if envValues.HasCrashReportFile() {
+		err = conf.SetCrashReportFile(envValues.GetCrashReportFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CrashReportFile from env params", args)
+		}
+	}
+
+	if envValues.HasCompressionMinSize() {
+		err = conf.SetCompressionMinSize(envValues.GetCompressionMinSize())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CompressionMinSize from env params", args)
+		}
+	}
+
+	if envValues.HasCompressionContentTypes() {
+		err = conf.SetCompressionContentTypes(envValues.GetCompressionContentTypes())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CompressionContentTypes from env params", args)
+		}
+	}
+
+	if envValues.HasAppMode() {
+		err = conf.SetAppMode(envValues.GetAppMode())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set AppMode from env params", args)
+		}
+	}
 }
 
 func getFlagsValues() {
@@ -114,6 +505,390 @@ func getFlagsValues() {
 			logger.Error("fail set JWTSecret from flag params", args)
 		}
 	}
+
+	if flagValues.HasLoginMaxAttempts() {
+		err = conf.SetLoginMaxAttempts(flagValues.GetLoginMaxAttempts())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set LoginMaxAttempts from flag params", args)
+		}
+	}
+
+	if flagValues.HasLoginIPMaxAttempts() {
+		err = conf.SetLoginIPMaxAttempts(flagValues.GetLoginIPMaxAttempts())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set LoginIPMaxAttempts from flag params", args)
+		}
+	}
+
+	if flagValues.HasLoginLockoutDuration() {
+		err = conf.SetLoginLockoutDuration(flagValues.GetLoginLockoutDuration())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set LoginLockoutDuration from flag params", args)
+		}
+	}
+
+	if flagValues.HasAdminLogins() {
+		err = conf.SetAdminLogins(flagValues.GetAdminLogins())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set AdminLogins from flag params", args)
+		}
+	}
+
+	if flagValues.HasAuthTokenPrecedence() {
+		err = conf.SetAuthTokenPrecedence(flagValues.GetAuthTokenPrecedence())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set AuthTokenPrecedence from flag params", args)
+		}
+	}
+
+	if flagValues.HasJWTKeysFile() {
+		err = conf.SetJWTKeysFile(flagValues.GetJWTKeysFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set JWTKeysFile from flag params", args)
+		}
+	}
+
+	if flagValues.HasPasswordHasher() {
+		err = conf.SetPasswordHasher(flagValues.GetPasswordHasher())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set PasswordHasher from flag params", args)
+		}
+	}
+
+	if flagValues.HasBcryptCost() {
+		err = conf.SetBcryptCost(flagValues.GetBcryptCost())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set BcryptCost from flag params", args)
+		}
+	}
+
+	if flagValues.HasArgon2Memory() {
+		err = conf.SetArgon2Memory(flagValues.GetArgon2Memory())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set Argon2Memory from flag params", args)
+		}
+	}
+
+	if flagValues.HasArgon2Iterations() {
+		err = conf.SetArgon2Iterations(flagValues.GetArgon2Iterations())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set Argon2Iterations from flag params", args)
+		}
+	}
+
+	if flagValues.HasArgon2Parallelism() {
+		err = conf.SetArgon2Parallelism(flagValues.GetArgon2Parallelism())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set Argon2Parallelism from flag params", args)
+		}
+	}
+
+	if flagValues.HasPasswordMinLength() {
+		err = conf.SetPasswordMinLength(flagValues.GetPasswordMinLength())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set PasswordMinLength from flag params", args)
+		}
+	}
+
+	if flagValues.HasPasswordMinClasses() {
+		err = conf.SetPasswordMinClasses(flagValues.GetPasswordMinClasses())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set PasswordMinClasses from flag params", args)
+		}
+	}
+
+	if flagValues.HasNotifier() {
+		err = conf.SetNotifier(flagValues.GetNotifier())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set Notifier from flag params", args)
+		}
+	}
+
+	if flagValues.HasNotifierFile() {
+		err = conf.SetNotifierFile(flagValues.GetNotifierFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set NotifierFile from flag params", args)
+		}
+	}
+
+	if flagValues.HasSMTPHost() {
+		err = conf.SetSMTPHost(flagValues.GetSMTPHost())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SMTPHost from flag params", args)
+		}
+	}
+
+	if flagValues.HasSMTPPort() {
+		err = conf.SetSMTPPort(flagValues.GetSMTPPort())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SMTPPort from flag params", args)
+		}
+	}
+
+	if flagValues.HasSMTPUsername() {
+		err = conf.SetSMTPUsername(flagValues.GetSMTPUsername())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SMTPUsername from flag params", args)
+		}
+	}
+
+	if flagValues.HasSMTPPassword() {
+		err = conf.SetSMTPPassword(flagValues.GetSMTPPassword())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SMTPPassword from flag params", args)
+		}
+	}
+
+	if flagValues.HasSMTPFrom() {
+		err = conf.SetSMTPFrom(flagValues.GetSMTPFrom())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SMTPFrom from flag params", args)
+		}
+	}
+
+	if flagValues.HasPasswordResetTTL() {
+		err = conf.SetPasswordResetTTL(flagValues.GetPasswordResetTTL())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set PasswordResetTTL from flag params", args)
+		}
+	}
+
+	if flagValues.HasAdjustmentApprovalThreshold() {
+		err = conf.SetAdjustmentApprovalThreshold(flagValues.GetAdjustmentApprovalThreshold())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set AdjustmentApprovalThreshold from flag params", args)
+		}
+	}
+
+	if flagValues.HasOIDCIssuer() {
+		err = conf.SetOIDCIssuer(flagValues.GetOIDCIssuer())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCIssuer from flag params", args)
+		}
+	}
+
+	if flagValues.HasOIDCClientID() {
+		err = conf.SetOIDCClientID(flagValues.GetOIDCClientID())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCClientID from flag params", args)
+		}
+	}
+
+	if flagValues.HasOIDCClientSecret() {
+		err = conf.SetOIDCClientSecret(flagValues.GetOIDCClientSecret())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCClientSecret from flag params", args)
+		}
+	}
+
+	if flagValues.HasOIDCRedirectURL() {
+		err = conf.SetOIDCRedirectURL(flagValues.GetOIDCRedirectURL())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCRedirectURL from flag params", args)
+		}
+	}
+
+	if flagValues.HasOIDCRoleClaim() {
+		err = conf.SetOIDCRoleClaim(flagValues.GetOIDCRoleClaim())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCRoleClaim from flag params", args)
+		}
+	}
+
+	if flagValues.HasOIDCRoleMapping() {
+		err = conf.SetOIDCRoleMapping(flagValues.GetOIDCRoleMapping())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set OIDCRoleMapping from flag params", args)
+		}
+	}
+
+	if flagValues.HasCookieSecure() {
+		err = conf.SetCookieSecure(flagValues.GetCookieSecure())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CookieSecure from flag params", args)
+		}
+	}
+
+	if flagValues.HasCookieSameSite() {
+		err = conf.SetCookieSameSite(flagValues.GetCookieSameSite())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CookieSameSite from flag params", args)
+		}
+	}
+
+	if flagValues.HasCookieDomain() {
+		err = conf.SetCookieDomain(flagValues.GetCookieDomain())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CookieDomain from flag params", args)
+		}
+	}
+
+	if flagValues.HasSessionRenewalThreshold() {
+		err = conf.SetSessionRenewalThreshold(flagValues.GetSessionRenewalThreshold())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SessionRenewalThreshold from flag params", args)
+		}
+	}
+
+	if flagValues.HasSessionMaxLifetime() {
+		err = conf.SetSessionMaxLifetime(flagValues.GetSessionMaxLifetime())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set SessionMaxLifetime from flag params", args)
+		}
+	}
+
+	if flagValues.HasTLSCertFile() {
+		err = conf.SetTLSCertFile(flagValues.GetTLSCertFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set TLSCertFile from flag params", args)
+		}
+	}
+
+	if flagValues.HasTLSKeyFile() {
+		err = conf.SetTLSKeyFile(flagValues.GetTLSKeyFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set TLSKeyFile from flag params", args)
+		}
+	}
+
+	if flagValues.HasTLSClientCAFile() {
+		err = conf.SetTLSClientCAFile(flagValues.GetTLSClientCAFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set TLSClientCAFile from flag params", args)
+		}
+	}
+
+	if flagValues.HasTLSMinVersion() {
+		err = conf.SetTLSMinVersion(flagValues.GetTLSMinVersion())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set TLSMinVersion from flag params", args)
+		}
+	}
+
+	if flagValues.HasTLSCipherSuites() {
+		err = conf.SetTLSCipherSuites(flagValues.GetTLSCipherSuites())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set TLSCipherSuites from flag params", args)
+		}
+	}
+
+	if flagValues.HasServerReadHeaderTimeout() {
+		err = conf.SetServerReadHeaderTimeout(flagValues.GetServerReadHeaderTimeout())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set ServerReadHeaderTimeout from flag params", args)
+		}
+	}
+
+	if flagValues.HasServerReadTimeout() {
+		err = conf.SetServerReadTimeout(flagValues.GetServerReadTimeout())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set ServerReadTimeout from flag params", args)
+		}
+	}
+
+	if flagValues.HasServerWriteTimeout() {
+		err = conf.SetServerWriteTimeout(flagValues.GetServerWriteTimeout())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set ServerWriteTimeout from flag params", args)
+		}
+	}
+
+	if flagValues.HasServerIdleTimeout() {
+		err = conf.SetServerIdleTimeout(flagValues.GetServerIdleTimeout())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set ServerIdleTimeout from flag params", args)
+		}
+	}
+
+	if flagValues.HasMaxRequestBodySize() {
+		err = conf.SetMaxRequestBodySize(flagValues.GetMaxRequestBodySize())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set MaxRequestBodySize from flag params", args)
+		}
+	}
+
+	if flagValues.HasCrashReportSink() {
+		err = conf.SetCrashReportSink(flagValues.GetCrashReportSink())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CrashReportSink from flag params", args)
+		}
+	}
+
+	if flagValues.HasCrashReportFile() {
+		err = conf.SetCrashReportFile(flagValues.GetCrashReportFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CrashReportFile from flag params", args)
+		}
+	}
+
+	if flagValues.HasCompressionMinSize() {
+		err = conf.SetCompressionMinSize(flagValues.GetCompressionMinSize())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CompressionMinSize from flag params", args)
+		}
+	}
+
+	if flagValues.HasCompressionContentTypes() {
+		err = conf.SetCompressionContentTypes(flagValues.GetCompressionContentTypes())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set CompressionContentTypes from flag params", args)
+		}
+	}
+
+	if flagValues.HasAppMode() {
+		err = conf.SetAppMode(flagValues.GetAppMode())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail set AppMode from flag params", args)
+		}
+	}
 }
 
 func getRandomSecret() string {
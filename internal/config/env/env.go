@@ -2,6 +2,9 @@ package env
 
 import (
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/MagicNetLab/go-diploma/internal/services/logger"
 	"github.com/joho/godotenv"
@@ -36,5 +39,253 @@ func Parse() (Options, error) {
 		opts.jwtSecret = jwtSecret
 	}
 
+	if v := os.Getenv(loginMaxAttemptsKey); v != "" {
+		opts.loginMaxAttempts = parseInt(loginMaxAttemptsKey, v)
+	}
+
+	if v := os.Getenv(loginIPMaxAttemptsKey); v != "" {
+		opts.loginIPMaxAttempts = parseInt(loginIPMaxAttemptsKey, v)
+	}
+
+	if v := os.Getenv(loginLockoutDurationKey); v != "" {
+		opts.loginLockoutDuration = parseDuration(loginLockoutDurationKey, v)
+	}
+
+	if v := os.Getenv(adminLoginsKey); v != "" {
+		opts.adminLogins = parseList(v)
+	}
+
+	if v := os.Getenv(authTokenPrecedenceKey); v != "" {
+		opts.authTokenPrecedence = v
+	}
+
+	if v := os.Getenv(jwtKeysFileKey); v != "" {
+		opts.jwtKeysFile = v
+	}
+
+	if v := os.Getenv(passwordHasherKey); v != "" {
+		opts.passwordHasher = v
+	}
+
+	if v := os.Getenv(bcryptCostKey); v != "" {
+		opts.bcryptCost = parseInt(bcryptCostKey, v)
+	}
+
+	if v := os.Getenv(argon2MemoryKey); v != "" {
+		opts.argon2Memory = parseInt(argon2MemoryKey, v)
+	}
+
+	if v := os.Getenv(argon2IterationsKey); v != "" {
+		opts.argon2Iterations = parseInt(argon2IterationsKey, v)
+	}
+
+	if v := os.Getenv(argon2ParallelismKey); v != "" {
+		opts.argon2Parallelism = parseInt(argon2ParallelismKey, v)
+	}
+
+	if v := os.Getenv(passwordMinLengthKey); v != "" {
+		opts.passwordMinLength = parseInt(passwordMinLengthKey, v)
+	}
+
+	if v := os.Getenv(passwordMinClassesKey); v != "" {
+		opts.passwordMinClasses = parseInt(passwordMinClassesKey, v)
+	}
+
+	if v := os.Getenv(notifierKey); v != "" {
+		opts.notifier = v
+	}
+
+	if v := os.Getenv(notifierFileKey); v != "" {
+		opts.notifierFile = v
+	}
+
+	if v := os.Getenv(smtpHostKey); v != "" {
+		opts.smtpHost = v
+	}
+
+	if v := os.Getenv(smtpPortKey); v != "" {
+		opts.smtpPort = parseInt(smtpPortKey, v)
+	}
+
+	if v := os.Getenv(smtpUsernameKey); v != "" {
+		opts.smtpUsername = v
+	}
+
+	if v := os.Getenv(smtpPasswordKey); v != "" {
+		opts.smtpPassword = v
+	}
+
+	if v := os.Getenv(smtpFromKey); v != "" {
+		opts.smtpFrom = v
+	}
+
+	if v := os.Getenv(passwordResetTTLKey); v != "" {
+		opts.passwordResetTTL = parseDuration(passwordResetTTLKey, v)
+	}
+
+	if v := os.Getenv(adjustmentApprovalThresholdKey); v != "" {
+		opts.adjustmentApprovalThreshold = parseFloat(adjustmentApprovalThresholdKey, v)
+	}
+
+	if v := os.Getenv(oidcIssuerKey); v != "" {
+		opts.oidcIssuer = v
+	}
+
+	if v := os.Getenv(oidcClientIDKey); v != "" {
+		opts.oidcClientID = v
+	}
+
+	if v := os.Getenv(oidcClientSecretKey); v != "" {
+		opts.oidcClientSecret = v
+	}
+
+	if v := os.Getenv(oidcRedirectURLKey); v != "" {
+		opts.oidcRedirectURL = v
+	}
+
+	if v := os.Getenv(oidcRoleClaimKey); v != "" {
+		opts.oidcRoleClaim = v
+	}
+
+	if v := os.Getenv(oidcRoleMappingKey); v != "" {
+		opts.oidcRoleMapping = v
+	}
+
+	if v := os.Getenv(cookieSecureKey); v != "" {
+		opts.cookieSecure = parseBool(cookieSecureKey, v)
+	}
+
+	if v := os.Getenv(cookieSameSiteKey); v != "" {
+		opts.cookieSameSite = v
+	}
+
+	if v := os.Getenv(cookieDomainKey); v != "" {
+		opts.cookieDomain = v
+	}
+
+	if v := os.Getenv(sessionRenewalThresholdKey); v != "" {
+		opts.sessionRenewalThreshold = parseDuration(sessionRenewalThresholdKey, v)
+	}
+
+	if v := os.Getenv(sessionMaxLifetimeKey); v != "" {
+		opts.sessionMaxLifetime = parseDuration(sessionMaxLifetimeKey, v)
+	}
+
+	if v := os.Getenv(tlsCertFileKey); v != "" {
+		opts.tlsCertFile = v
+	}
+
+	if v := os.Getenv(tlsKeyFileKey); v != "" {
+		opts.tlsKeyFile = v
+	}
+
+	if v := os.Getenv(tlsClientCAFileKey); v != "" {
+		opts.tlsClientCAFile = v
+	}
+
+	if v := os.Getenv(tlsMinVersionKey); v != "" {
+		opts.tlsMinVersion = v
+	}
+
+	if v := os.Getenv(tlsCipherSuitesKey); v != "" {
+		opts.tlsCipherSuites = v
+	}
+
+	if v := os.Getenv(serverReadHeaderTimeoutKey); v != "" {
+		opts.serverReadHeaderTimeout = parseDuration(serverReadHeaderTimeoutKey, v)
+	}
+
+	if v := os.Getenv(serverReadTimeoutKey); v != "" {
+		opts.serverReadTimeout = parseDuration(serverReadTimeoutKey, v)
+	}
+
+	if v := os.Getenv(serverWriteTimeoutKey); v != "" {
+		opts.serverWriteTimeout = parseDuration(serverWriteTimeoutKey, v)
+	}
+
+	if v := os.Getenv(serverIdleTimeoutKey); v != "" {
+		opts.serverIdleTimeout = parseDuration(serverIdleTimeoutKey, v)
+	}
+
+	if v := os.Getenv(maxRequestBodySizeKey); v != "" {
+		opts.maxRequestBodySize = int64(parseInt(maxRequestBodySizeKey, v))
+	}
+
+	if v := os.Getenv(crashReportSinkKey); v != "" {
+		opts.crashReportSink = v
+	}
+
+	if v := os.Getenv(crashReportFileKey); v != "" {
+		opts.crashReportFile = v
+	}
+
+	if v := os.Getenv(compressionMinSizeKey); v != "" {
+		opts.compressionMinSize = parseInt(compressionMinSizeKey, v)
+	}
+
+	if v := os.Getenv(compressionContentTypesKey); v != "" {
+		opts.compressionContentTypes = v
+	}
+
+	if v := os.Getenv(appModeKey); v != "" {
+		opts.appMode = v
+	}
+
 	return opts, nil
 }
+
+func parseInt(key string, value string) int {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "key": key}
+		logger.Error("fail parse int env param", args)
+		return 0
+	}
+
+	return i
+}
+
+func parseDuration(key string, value string) time.Duration {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "key": key}
+		logger.Error("fail parse duration env param", args)
+		return 0
+	}
+
+	return d
+}
+
+func parseFloat(key string, value string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "key": key}
+		logger.Error("fail parse float env param", args)
+		return 0
+	}
+
+	return f
+}
+
+func parseBool(key string, value string) bool {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "key": key}
+		logger.Error("fail parse bool env param", args)
+		return false
+	}
+
+	return b
+}
+
+func parseList(value string) []string {
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
@@ -1,10 +1,61 @@
 package env
 
+import "time"
+
 const (
 	runAddressKey       = "RUN_ADDRESS"
 	dbURIKey            = "DATABASE_URI"
 	accrualSystemURLKey = "ACCRUAL_SYSTEM_ADDRESS"
 	jwtSecret           = "JWT_SECRET"
+
+	loginMaxAttemptsKey            = "LOGIN_MAX_ATTEMPTS"
+	loginIPMaxAttemptsKey          = "LOGIN_IP_MAX_ATTEMPTS"
+	loginLockoutDurationKey        = "LOGIN_LOCKOUT_DURATION"
+	adminLoginsKey                 = "ADMIN_LOGINS"
+	authTokenPrecedenceKey         = "AUTH_TOKEN_PRECEDENCE"
+	jwtKeysFileKey                 = "JWT_KEYS_FILE"
+	passwordHasherKey              = "PASSWORD_HASHER"
+	bcryptCostKey                  = "BCRYPT_COST"
+	argon2MemoryKey                = "ARGON2_MEMORY"
+	argon2IterationsKey            = "ARGON2_ITERATIONS"
+	argon2ParallelismKey           = "ARGON2_PARALLELISM"
+	passwordMinLengthKey           = "PASSWORD_MIN_LENGTH"
+	passwordMinClassesKey          = "PASSWORD_MIN_CLASSES"
+	notifierKey                    = "NOTIFIER"
+	notifierFileKey                = "NOTIFIER_FILE"
+	smtpHostKey                    = "SMTP_HOST"
+	smtpPortKey                    = "SMTP_PORT"
+	smtpUsernameKey                = "SMTP_USERNAME"
+	smtpPasswordKey                = "SMTP_PASSWORD"
+	smtpFromKey                    = "SMTP_FROM"
+	passwordResetTTLKey            = "PASSWORD_RESET_TTL"
+	adjustmentApprovalThresholdKey = "ADJUSTMENT_APPROVAL_THRESHOLD"
+	oidcIssuerKey                  = "OIDC_ISSUER"
+	oidcClientIDKey                = "OIDC_CLIENT_ID"
+	oidcClientSecretKey            = "OIDC_CLIENT_SECRET"
+	oidcRedirectURLKey             = "OIDC_REDIRECT_URL"
+	oidcRoleClaimKey               = "OIDC_ROLE_CLAIM"
+	oidcRoleMappingKey             = "OIDC_ROLE_MAPPING"
+	cookieSecureKey                = "COOKIE_SECURE"
+	cookieSameSiteKey              = "COOKIE_SAMESITE"
+	cookieDomainKey                = "COOKIE_DOMAIN"
+	sessionRenewalThresholdKey     = "SESSION_RENEWAL_THRESHOLD"
+	sessionMaxLifetimeKey          = "SESSION_MAX_LIFETIME"
+	tlsCertFileKey                 = "TLS_CERT_FILE"
+	tlsKeyFileKey                  = "TLS_KEY_FILE"
+	tlsClientCAFileKey             = "TLS_CLIENT_CA_FILE"
+	tlsMinVersionKey               = "TLS_MIN_VERSION"
+	tlsCipherSuitesKey             = "TLS_CIPHER_SUITES"
+	serverReadHeaderTimeoutKey     = "SERVER_READ_HEADER_TIMEOUT"
+	serverReadTimeoutKey           = "SERVER_READ_TIMEOUT"
+	serverWriteTimeoutKey          = "SERVER_WRITE_TIMEOUT"
+	serverIdleTimeoutKey           = "SERVER_IDLE_TIMEOUT"
+	maxRequestBodySizeKey          = "MAX_REQUEST_BODY_SIZE"
+	crashReportSinkKey             = "CRASH_REPORT_SINK"
+	crashReportFileKey             = "CRASH_REPORT_FILE"
+	compressionMinSizeKey          = "COMPRESSION_MIN_SIZE"
+	compressionContentTypesKey     = "COMPRESSION_CONTENT_TYPES"
+	appModeKey                     = "APP_MODE"
 )
 
 type Options struct {
@@ -12,6 +63,55 @@ type Options struct {
 	dbURI            string `env:"DATABASE_URI"`
 	accrualSystemURL string `env:"ACCRUAL_SYSTEM_ADDRESS"`
 	jwtSecret        string `env:"JWT_SECRET"`
+
+	loginMaxAttempts            int           `env:"LOGIN_MAX_ATTEMPTS"`
+	loginIPMaxAttempts          int           `env:"LOGIN_IP_MAX_ATTEMPTS"`
+	loginLockoutDuration        time.Duration `env:"LOGIN_LOCKOUT_DURATION"`
+	adminLogins                 []string      `env:"ADMIN_LOGINS"`
+	authTokenPrecedence         string        `env:"AUTH_TOKEN_PRECEDENCE"`
+	jwtKeysFile                 string        `env:"JWT_KEYS_FILE"`
+	passwordHasher              string        `env:"PASSWORD_HASHER"`
+	bcryptCost                  int           `env:"BCRYPT_COST"`
+	argon2Memory                int           `env:"ARGON2_MEMORY"`
+	argon2Iterations            int           `env:"ARGON2_ITERATIONS"`
+	argon2Parallelism           int           `env:"ARGON2_PARALLELISM"`
+	passwordMinLength           int           `env:"PASSWORD_MIN_LENGTH"`
+	passwordMinClasses          int           `env:"PASSWORD_MIN_CLASSES"`
+	notifier                    string        `env:"NOTIFIER"`
+	notifierFile                string        `env:"NOTIFIER_FILE"`
+	smtpHost                    string        `env:"SMTP_HOST"`
+	smtpPort                    int           `env:"SMTP_PORT"`
+	smtpUsername                string        `env:"SMTP_USERNAME"`
+	smtpPassword                string        `env:"SMTP_PASSWORD"`
+	smtpFrom                    string        `env:"SMTP_FROM"`
+	passwordResetTTL            time.Duration `env:"PASSWORD_RESET_TTL"`
+	adjustmentApprovalThreshold float64       `env:"ADJUSTMENT_APPROVAL_THRESHOLD"`
+	oidcIssuer                  string        `env:"OIDC_ISSUER"`
+	oidcClientID                string        `env:"OIDC_CLIENT_ID"`
+	oidcClientSecret            string        `env:"OIDC_CLIENT_SECRET"`
+	oidcRedirectURL             string        `env:"OIDC_REDIRECT_URL"`
+	oidcRoleClaim               string        `env:"OIDC_ROLE_CLAIM"`
+	oidcRoleMapping             string        `env:"OIDC_ROLE_MAPPING"`
+	cookieSecure                bool          `env:"COOKIE_SECURE"`
+	cookieSameSite              string        `env:"COOKIE_SAMESITE"`
+	cookieDomain                string        `env:"COOKIE_DOMAIN"`
+	sessionRenewalThreshold     time.Duration `env:"SESSION_RENEWAL_THRESHOLD"`
+	sessionMaxLifetime          time.Duration `env:"SESSION_MAX_LIFETIME"`
+	tlsCertFile                 string        `env:"TLS_CERT_FILE"`
+	tlsKeyFile                  string        `env:"TLS_KEY_FILE"`
+	tlsClientCAFile             string        `env:"TLS_CLIENT_CA_FILE"`
+	tlsMinVersion               string        `env:"TLS_MIN_VERSION"`
+	tlsCipherSuites             string        `env:"TLS_CIPHER_SUITES"`
+	serverReadHeaderTimeout     time.Duration `env:"SERVER_READ_HEADER_TIMEOUT"`
+	serverReadTimeout           time.Duration `env:"SERVER_READ_TIMEOUT"`
+	serverWriteTimeout          time.Duration `env:"SERVER_WRITE_TIMEOUT"`
+	serverIdleTimeout           time.Duration `env:"SERVER_IDLE_TIMEOUT"`
+	maxRequestBodySize          int64         `env:"MAX_REQUEST_BODY_SIZE"`
+	crashReportSink             string        `env:"CRASH_REPORT_SINK"`
+	crashReportFile             string        `env:"CRASH_REPORT_FILE"`
+	compressionMinSize          int           `env:"COMPRESSION_MIN_SIZE"`
+	compressionContentTypes     string        `env:"COMPRESSION_CONTENT_TYPES"`
+	appMode                     string        `env:"APP_MODE"`
 }
 
 func (o *Options) HasRunAddress() bool {
@@ -45,3 +145,387 @@ func (o *Options) HasJWTSecret() bool {
 func (o *Options) GetJWTSecret() string {
 	return o.jwtSecret
 }
+
+func (o *Options) HasLoginMaxAttempts() bool {
+	return o.loginMaxAttempts != 0
+}
+
+func (o *Options) GetLoginMaxAttempts() int {
+	return o.loginMaxAttempts
+}
+
+func (o *Options) HasLoginIPMaxAttempts() bool {
+	return o.loginIPMaxAttempts != 0
+}
+
+func (o *Options) GetLoginIPMaxAttempts() int {
+	return o.loginIPMaxAttempts
+}
+
+func (o *Options) HasLoginLockoutDuration() bool {
+	return o.loginLockoutDuration != 0
+}
+
+func (o *Options) GetLoginLockoutDuration() time.Duration {
+	return o.loginLockoutDuration
+}
+
+func (o *Options) HasAdminLogins() bool {
+	return len(o.adminLogins) > 0
+}
+
+func (o *Options) GetAdminLogins() []string {
+	return o.adminLogins
+}
+
+func (o *Options) HasAuthTokenPrecedence() bool {
+	return o.authTokenPrecedence != ""
+}
+
+func (o *Options) GetAuthTokenPrecedence() string {
+	return o.authTokenPrecedence
+}
+
+func (o *Options) HasJWTKeysFile() bool {
+	return o.jwtKeysFile != ""
+}
+
+func (o *Options) GetJWTKeysFile() string {
+	return o.jwtKeysFile
+}
+
+func (o *Options) HasPasswordHasher() bool {
+	return o.passwordHasher != ""
+}
+
+func (o *Options) GetPasswordHasher() string {
+	return o.passwordHasher
+}
+
+func (o *Options) HasBcryptCost() bool {
+	return o.bcryptCost != 0
+}
+
+func (o *Options) GetBcryptCost() int {
+	return o.bcryptCost
+}
+
+func (o *Options) HasArgon2Memory() bool {
+	return o.argon2Memory != 0
+}
+
+func (o *Options) GetArgon2Memory() int {
+	return o.argon2Memory
+}
+
+func (o *Options) HasArgon2Iterations() bool {
+	return o.argon2Iterations != 0
+}
+
+func (o *Options) GetArgon2Iterations() int {
+	return o.argon2Iterations
+}
+
+func (o *Options) HasArgon2Parallelism() bool {
+	return o.argon2Parallelism != 0
+}
+
+func (o *Options) GetArgon2Parallelism() int {
+	return o.argon2Parallelism
+}
+
+func (o *Options) HasPasswordMinLength() bool {
+	return o.passwordMinLength != 0
+}
+
+func (o *Options) GetPasswordMinLength() int {
+	return o.passwordMinLength
+}
+
+func (o *Options) HasPasswordMinClasses() bool {
+	return o.passwordMinClasses != 0
+}
+
+func (o *Options) GetPasswordMinClasses() int {
+	return o.passwordMinClasses
+}
+
+func (o *Options) HasNotifier() bool {
+	return o.notifier != ""
+}
+
+func (o *Options) GetNotifier() string {
+	return o.notifier
+}
+
+func (o *Options) HasNotifierFile() bool {
+	return o.notifierFile != ""
+}
+
+func (o *Options) GetNotifierFile() string {
+	return o.notifierFile
+}
+
+func (o *Options) HasSMTPHost() bool {
+	return o.smtpHost != ""
+}
+
+func (o *Options) GetSMTPHost() string {
+	return o.smtpHost
+}
+
+func (o *Options) HasSMTPPort() bool {
+	return o.smtpPort != 0
+}
+
+func (o *Options) GetSMTPPort() int {
+	return o.smtpPort
+}
+
+func (o *Options) HasSMTPUsername() bool {
+	return o.smtpUsername != ""
+}
+
+func (o *Options) GetSMTPUsername() string {
+	return o.smtpUsername
+}
+
+func (o *Options) HasSMTPPassword() bool {
+	return o.smtpPassword != ""
+}
+
+func (o *Options) GetSMTPPassword() string {
+	return o.smtpPassword
+}
+
+func (o *Options) HasSMTPFrom() bool {
+	return o.smtpFrom != ""
+}
+
+func (o *Options) GetSMTPFrom() string {
+	return o.smtpFrom
+}
+
+func (o *Options) HasPasswordResetTTL() bool {
+	return o.passwordResetTTL != 0
+}
+
+func (o *Options) GetPasswordResetTTL() time.Duration {
+	return o.passwordResetTTL
+}
+
+func (o *Options) HasAdjustmentApprovalThreshold() bool {
+	return o.adjustmentApprovalThreshold != 0
+}
+
+func (o *Options) GetAdjustmentApprovalThreshold() float64 {
+	return o.adjustmentApprovalThreshold
+}
+
+func (o *Options) HasOIDCIssuer() bool {
+	return o.oidcIssuer != ""
+}
+
+func (o *Options) GetOIDCIssuer() string {
+	return o.oidcIssuer
+}
+
+func (o *Options) HasOIDCClientID() bool {
+	return o.oidcClientID != ""
+}
+
+func (o *Options) GetOIDCClientID() string {
+	return o.oidcClientID
+}
+
+func (o *Options) HasOIDCClientSecret() bool {
+	return o.oidcClientSecret != ""
+}
+
+func (o *Options) GetOIDCClientSecret() string {
+	return o.oidcClientSecret
+}
+
+func (o *Options) HasOIDCRedirectURL() bool {
+	return o.oidcRedirectURL != ""
+}
+
+func (o *Options) GetOIDCRedirectURL() string {
+	return o.oidcRedirectURL
+}
+
+func (o *Options) HasOIDCRoleClaim() bool {
+	return o.oidcRoleClaim != ""
+}
+
+func (o *Options) GetOIDCRoleClaim() string {
+	return o.oidcRoleClaim
+}
+
+func (o *Options) HasOIDCRoleMapping() bool {
+	return o.oidcRoleMapping != ""
+}
+
+func (o *Options) GetOIDCRoleMapping() string {
+	return o.oidcRoleMapping
+}
+
+func (o *Options) HasCookieSecure() bool {
+	return o.cookieSecure
+}
+
+func (o *Options) GetCookieSecure() bool {
+	return o.cookieSecure
+}
+
+func (o *Options) HasCookieSameSite() bool {
+	return o.cookieSameSite != ""
+}
+
+func (o *Options) GetCookieSameSite() string {
+	return o.cookieSameSite
+}
+
+func (o *Options) HasCookieDomain() bool {
+	return o.cookieDomain != ""
+}
+
+func (o *Options) GetCookieDomain() string {
+	return o.cookieDomain
+}
+
+func (o *Options) HasSessionRenewalThreshold() bool {
+	return o.sessionRenewalThreshold != 0
+}
+
+func (o *Options) GetSessionRenewalThreshold() time.Duration {
+	return o.sessionRenewalThreshold
+}
+
+func (o *Options) HasSessionMaxLifetime() bool {
+	return o.sessionMaxLifetime != 0
+}
+
+func (o *Options) GetSessionMaxLifetime() time.Duration {
+	return o.sessionMaxLifetime
+}
+
+func (o *Options) HasTLSCertFile() bool {
+	return o.tlsCertFile != ""
+}
+
+func (o *Options) GetTLSCertFile() string {
+	return o.tlsCertFile
+}
+
+func (o *Options) HasTLSKeyFile() bool {
+	return o.tlsKeyFile != ""
+}
+
+func (o *Options) GetTLSKeyFile() string {
+	return o.tlsKeyFile
+}
+
+func (o *Options) HasTLSClientCAFile() bool {
+	return o.tlsClientCAFile != ""
+}
+
+func (o *Options) GetTLSClientCAFile() string {
+	return o.tlsClientCAFile
+}
+
+func (o *Options) HasTLSMinVersion() bool {
+	return o.tlsMinVersion != ""
+}
+
+func (o *Options) GetTLSMinVersion() string {
+	return o.tlsMinVersion
+}
+
+func (o *Options) HasTLSCipherSuites() bool {
+	return o.tlsCipherSuites != ""
+}
+
+func (o *Options) GetTLSCipherSuites() string {
+	return o.tlsCipherSuites
+}
+
+func (o *Options) HasServerReadHeaderTimeout() bool {
+	return o.serverReadHeaderTimeout != 0
+}
+
+func (o *Options) GetServerReadHeaderTimeout() time.Duration {
+	return o.serverReadHeaderTimeout
+}
+
+func (o *Options) HasServerReadTimeout() bool {
+	return o.serverReadTimeout != 0
+}
+
+func (o *Options) GetServerReadTimeout() time.Duration {
+	return o.serverReadTimeout
+}
+
+func (o *Options) HasServerWriteTimeout() bool {
+	return o.serverWriteTimeout != 0
+}
+
+func (o *Options) GetServerWriteTimeout() time.Duration {
+	return o.serverWriteTimeout
+}
+
+func (o *Options) HasServerIdleTimeout() bool {
+	return o.serverIdleTimeout != 0
+}
+
+func (o *Options) GetServerIdleTimeout() time.Duration {
+	return o.serverIdleTimeout
+}
+
+func (o *Options) HasMaxRequestBodySize() bool {
+	return o.maxRequestBodySize != 0
+}
+
+func (o *Options) GetMaxRequestBodySize() int64 {
+	return o.maxRequestBodySize
+}
+
+func (o *Options) HasCrashReportSink() bool {
+	return o.crashReportSink != ""
+}
+
+func (o *Options) GetCrashReportSink() string {
+	return o.crashReportSink
+}
+
+func (o *Options) HasCrashReportFile() bool {
+	return o.crashReportFile != ""
+}
+
+func (o *Options) GetCrashReportFile() string {
+	return o.crashReportFile
+}
+
+func (o *Options) HasCompressionMinSize() bool {
+	return o.compressionMinSize != 0
+}
+
+func (o *Options) GetCompressionMinSize() int {
+	return o.compressionMinSize
+}
+
+func (o *Options) HasCompressionContentTypes() bool {
+	return o.compressionContentTypes != ""
+}
+
+func (o *Options) GetCompressionContentTypes() string {
+	return o.compressionContentTypes
+}
+
+func (o *Options) HasAppMode() bool {
+	return o.appMode != ""
+}
+
+func (o *Options) GetAppMode() string {
+	return o.appMode
+}
@@ -10,6 +10,54 @@ func Parse() (Options, error) {
 	flag.StringVar(&opts.dbURI, dbURIKey, "", "DB connection URI")
 	flag.StringVar(&opts.accrualSystemURL, accrualSystemURL, "", "Accrual system URL")
 	flag.StringVar(&opts.jwtSecret, jwtSecret, "", "JWT secret")
+	flag.IntVar(&opts.loginMaxAttempts, loginMaxAttemptsKey, 0, "Failed login attempts per login before lockout")
+	flag.IntVar(&opts.loginIPMaxAttempts, loginIPMaxAttemptsKey, 0, "Failed login attempts per IP before lockout")
+	flag.DurationVar(&opts.loginLockoutDuration, loginLockoutDurationKey, 0, "Login lockout duration")
+	flag.StringVar(&opts.adminLogins, adminLoginsKey, "", "Comma separated list of admin logins")
+	flag.StringVar(&opts.authTokenPrecedence, authTokenPrecedenceKey, "", "Token source checked first: header or cookie")
+	flag.StringVar(&opts.jwtKeysFile, jwtKeysFileKey, "", "JWT signing keys manifest file")
+	flag.StringVar(&opts.passwordHasher, passwordHasherKey, "", "Password hashing algorithm: argon2id or bcrypt")
+	flag.IntVar(&opts.bcryptCost, bcryptCostKey, 0, "Bcrypt cost")
+	flag.IntVar(&opts.argon2Memory, argon2MemoryKey, 0, "Argon2id memory in KiB, at most 1048576")
+	flag.IntVar(&opts.argon2Iterations, argon2IterationsKey, 0, "Argon2id iterations, at most 64")
+	flag.IntVar(&opts.argon2Parallelism, argon2ParallelismKey, 0, "Argon2id parallelism")
+	flag.IntVar(&opts.passwordMinLength, passwordMinLengthKey, 0, "Minimum password length")
+	flag.IntVar(&opts.passwordMinClasses, passwordMinClassesKey, 0, "Minimum number of character classes in a password")
+	flag.StringVar(&opts.notifier, notifierKey, "", "Notification sender: log, file or smtp")
+	flag.StringVar(&opts.notifierFile, notifierFileKey, "", "File for the file notifier")
+	flag.StringVar(&opts.smtpHost, smtpHostKey, "", "SMTP server host")
+	flag.IntVar(&opts.smtpPort, smtpPortKey, 0, "SMTP server port")
+	flag.StringVar(&opts.smtpUsername, smtpUsernameKey, "", "SMTP username")
+	flag.StringVar(&opts.smtpPassword, smtpPasswordKey, "", "SMTP password")
+	flag.StringVar(&opts.smtpFrom, smtpFromKey, "", "Sender address of notifications")
+	flag.DurationVar(&opts.passwordResetTTL, passwordResetTTLKey, 0, "Password reset token lifetime")
+	flag.Float64Var(&opts.adjustmentApprovalThreshold, adjustmentApprovalThresholdKey, 0, "Balance adjustments above this amount need approval of a second admin")
+	flag.StringVar(&opts.oidcIssuer, oidcIssuerKey, "", "OpenID Connect issuer URL, enables SSO login")
+	flag.StringVar(&opts.oidcClientID, oidcClientIDKey, "", "OpenID Connect client ID")
+	flag.StringVar(&opts.oidcClientSecret, oidcClientSecretKey, "", "OpenID Connect client secret")
+	flag.StringVar(&opts.oidcRedirectURL, oidcRedirectURLKey, "", "OpenID Connect redirect URL of the callback endpoint")
+	flag.StringVar(&opts.oidcRoleClaim, oidcRoleClaimKey, "", "ID token claim with the user groups")
+	flag.StringVar(&opts.oidcRoleMapping, oidcRoleMappingKey, "", "Comma separated group=role pairs mapping IdP groups to roles")
+	flag.BoolVar(&opts.cookieSecure, cookieSecureKey, false, "Always set the Secure attribute on auth cookies, e.g. behind a TLS terminating proxy")
+	flag.StringVar(&opts.cookieSameSite, cookieSameSiteKey, "", "SameSite attribute of auth cookies: strict, lax or none")
+	flag.StringVar(&opts.cookieDomain, cookieDomainKey, "", "Domain attribute of auth cookies, host-only cookies are used when empty")
+	flag.DurationVar(&opts.sessionRenewalThreshold, sessionRenewalThresholdKey, 0, "Renew the access token cookie when it expires sooner than this")
+	flag.DurationVar(&opts.sessionMaxLifetime, sessionMaxLifetimeKey, 0, "Absolute session lifetime since login, after which neither renewal nor refresh is possible")
+	flag.StringVar(&opts.tlsCertFile, tlsCertFileKey, "", "Path to the TLS certificate chain, TLS is enabled when set")
+	flag.StringVar(&opts.tlsKeyFile, tlsKeyFileKey, "", "Path to the TLS private key")
+	flag.StringVar(&opts.tlsClientCAFile, tlsClientCAFileKey, "", "CA bundle of client certificates required on admin routes")
+	flag.StringVar(&opts.tlsMinVersion, tlsMinVersionKey, "", "Minimum TLS version: 1.2 or 1.3")
+	flag.StringVar(&opts.tlsCipherSuites, tlsCipherSuitesKey, "", "Comma separated TLS 1.2 cipher suite names, Go defaults are used when empty")
+	flag.DurationVar(&opts.serverReadHeaderTimeout, serverReadHeaderTimeoutKey, 0, "Maximum time to read request headers")
+	flag.DurationVar(&opts.serverReadTimeout, serverReadTimeoutKey, 0, "Maximum time to read a whole request")
+	flag.DurationVar(&opts.serverWriteTimeout, serverWriteTimeoutKey, 0, "Maximum time to write a response")
+	flag.DurationVar(&opts.serverIdleTimeout, serverIdleTimeoutKey, 0, "Maximum time to keep an idle connection open")
+	flag.Int64Var(&opts.maxRequestBodySize, maxRequestBodySizeKey, 0, "Default maximum request body size in bytes, checked after decompression")
+	flag.StringVar(&opts.crashReportSink, crashReportSinkKey, "", "Where crash reports are written: log or file")
+	flag.StringVar(&opts.crashReportFile, crashReportFileKey, "", "File crash reports are appended to when the sink is file")
+	flag.IntVar(&opts.compressionMinSize, compressionMinSizeKey, 0, "Responses shorter than this many bytes are sent uncompressed")
+	flag.StringVar(&opts.compressionContentTypes, compressionContentTypesKey, "", "Comma separated content types which are compressed, a trailing * matches a prefix")
+	flag.StringVar(&opts.appMode, appModeKey, "", "Application mode: development or production")
 	flag.Parse()
 
 	return opts, nil
@@ -1,10 +1,64 @@
 package flags
 
+import (
+	"strings"
+	"time"
+)
+
 const (
 	runAddressKey    = "a"
 	dbURIKey         = "d"
 	accrualSystemURL = "r"
 	jwtSecret        = "s"
+
+	loginMaxAttemptsKey            = "login-max-attempts"
+	loginIPMaxAttemptsKey          = "login-ip-max-attempts"
+	loginLockoutDurationKey        = "login-lockout"
+	adminLoginsKey                 = "admins"
+	authTokenPrecedenceKey         = "auth-precedence"
+	jwtKeysFileKey                 = "jwt-keys"
+	passwordHasherKey              = "password-hasher"
+	bcryptCostKey                  = "bcrypt-cost"
+	argon2MemoryKey                = "argon2-memory"
+	argon2IterationsKey            = "argon2-iterations"
+	argon2ParallelismKey           = "argon2-parallelism"
+	passwordMinLengthKey           = "password-min-length"
+	passwordMinClassesKey          = "password-min-classes"
+	notifierKey                    = "notifier"
+	notifierFileKey                = "notifier-file"
+	smtpHostKey                    = "smtp-host"
+	smtpPortKey                    = "smtp-port"
+	smtpUsernameKey                = "smtp-username"
+	smtpPasswordKey                = "smtp-password"
+	smtpFromKey                    = "smtp-from"
+	passwordResetTTLKey            = "password-reset-ttl"
+	adjustmentApprovalThresholdKey = "adjustment-approval-threshold"
+	oidcIssuerKey                  = "oidc-issuer"
+	oidcClientIDKey                = "oidc-client-id"
+	oidcClientSecretKey            = "oidc-client-secret"
+	oidcRedirectURLKey             = "oidc-redirect-url"
+	oidcRoleClaimKey               = "oidc-role-claim"
+	oidcRoleMappingKey             = "oidc-role-mapping"
+	cookieSecureKey                = "cookie-secure"
+	cookieSameSiteKey              = "cookie-samesite"
+	cookieDomainKey                = "cookie-domain"
+	sessionRenewalThresholdKey     = "session-renewal-threshold"
+	sessionMaxLifetimeKey          = "session-max-lifetime"
+	tlsCertFileKey                 = "tls-cert-file"
+	tlsKeyFileKey                  = "tls-key-file"
+	tlsClientCAFileKey             = "tls-client-ca-file"
+	tlsMinVersionKey               = "tls-min-version"
+	tlsCipherSuitesKey             = "tls-cipher-suites"
+	serverReadHeaderTimeoutKey     = "server-read-header-timeout"
+	serverReadTimeoutKey           = "server-read-timeout"
+	serverWriteTimeoutKey          = "server-write-timeout"
+	serverIdleTimeoutKey           = "server-idle-timeout"
+	maxRequestBodySizeKey          = "max-request-body-size"
+	crashReportSinkKey             = "crash-report-sink"
+	crashReportFileKey             = "crash-report-file"
+	compressionMinSizeKey          = "compression-min-size"
+	compressionContentTypesKey     = "compression-content-types"
+	appModeKey                     = "app-mode"
 )
 
 type Options struct {
@@ -12,6 +66,55 @@ type Options struct {
 	dbURI            string
 	accrualSystemURL string
 	jwtSecret        string
+
+	loginMaxAttempts            int
+	loginIPMaxAttempts          int
+	loginLockoutDuration        time.Duration
+	adminLogins                 string
+	authTokenPrecedence         string
+	jwtKeysFile                 string
+	passwordHasher              string
+	bcryptCost                  int
+	argon2Memory                int
+	argon2Iterations            int
+	argon2Parallelism           int
+	passwordMinLength           int
+	passwordMinClasses          int
+	notifier                    string
+	notifierFile                string
+	smtpHost                    string
+	smtpPort                    int
+	smtpUsername                string
+	smtpPassword                string
+	smtpFrom                    string
+	passwordResetTTL            time.Duration
+	adjustmentApprovalThreshold float64
+	oidcIssuer                  string
+	oidcClientID                string
+	oidcClientSecret            string
+	oidcRedirectURL             string
+	oidcRoleClaim               string
+	oidcRoleMapping             string
+	cookieSecure                bool
+	cookieSameSite              string
+	cookieDomain                string
+	sessionRenewalThreshold     time.Duration
+	sessionMaxLifetime          time.Duration
+	tlsCertFile                 string
+	tlsKeyFile                  string
+	tlsClientCAFile             string
+	tlsMinVersion               string
+	tlsCipherSuites             string
+	serverReadHeaderTimeout     time.Duration
+	serverReadTimeout           time.Duration
+	serverWriteTimeout          time.Duration
+	serverIdleTimeout           time.Duration
+	maxRequestBodySize          int64
+	crashReportSink             string
+	crashReportFile             string
+	compressionMinSize          int
+	compressionContentTypes     string
+	appMode                     string
 }
 
 func (o *Options) HasRunAddress() bool {
@@ -45,3 +148,395 @@ func (o *Options) HasJWTSecret() bool {
 func (o *Options) GetJWTSecret() string {
 	return o.jwtSecret
 }
+
+func (o *Options) HasLoginMaxAttempts() bool {
+	return o.loginMaxAttempts != 0
+}
+
+func (o *Options) GetLoginMaxAttempts() int {
+	return o.loginMaxAttempts
+}
+
+func (o *Options) HasLoginIPMaxAttempts() bool {
+	return o.loginIPMaxAttempts != 0
+}
+
+func (o *Options) GetLoginIPMaxAttempts() int {
+	return o.loginIPMaxAttempts
+}
+
+func (o *Options) HasLoginLockoutDuration() bool {
+	return o.loginLockoutDuration != 0
+}
+
+func (o *Options) GetLoginLockoutDuration() time.Duration {
+	return o.loginLockoutDuration
+}
+
+func (o *Options) HasAdminLogins() bool {
+	return len(o.GetAdminLogins()) > 0
+}
+
+func (o *Options) GetAdminLogins() []string {
+	var list []string
+	for _, item := range strings.Split(o.adminLogins, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
+
+func (o *Options) HasAuthTokenPrecedence() bool {
+	return o.authTokenPrecedence != ""
+}
+
+func (o *Options) GetAuthTokenPrecedence() string {
+	return o.authTokenPrecedence
+}
+
+func (o *Options) HasJWTKeysFile() bool {
+	return o.jwtKeysFile != ""
+}
+
+func (o *Options) GetJWTKeysFile() string {
+	return o.jwtKeysFile
+}
+
+func (o *Options) HasPasswordHasher() bool {
+	return o.passwordHasher != ""
+}
+
+func (o *Options) GetPasswordHasher() string {
+	return o.passwordHasher
+}
+
+func (o *Options) HasBcryptCost() bool {
+	return o.bcryptCost != 0
+}
+
+func (o *Options) GetBcryptCost() int {
+	return o.bcryptCost
+}
+
+func (o *Options) HasArgon2Memory() bool {
+	return o.argon2Memory != 0
+}
+
+func (o *Options) GetArgon2Memory() int {
+	return o.argon2Memory
+}
+
+func (o *Options) HasArgon2Iterations() bool {
+	return o.argon2Iterations != 0
+}
+
+func (o *Options) GetArgon2Iterations() int {
+	return o.argon2Iterations
+}
+
+func (o *Options) HasArgon2Parallelism() bool {
+	return o.argon2Parallelism != 0
+}
+
+func (o *Options) GetArgon2Parallelism() int {
+	return o.argon2Parallelism
+}
+
+func (o *Options) HasPasswordMinLength() bool {
+	return o.passwordMinLength != 0
+}
+
+func (o *Options) GetPasswordMinLength() int {
+	return o.passwordMinLength
+}
+
+func (o *Options) HasPasswordMinClasses() bool {
+	return o.passwordMinClasses != 0
+}
+
+func (o *Options) GetPasswordMinClasses() int {
+	return o.passwordMinClasses
+}
+
+func (o *Options) HasNotifier() bool {
+	return o.notifier != ""
+}
+
+func (o *Options) GetNotifier() string {
+	return o.notifier
+}
+
+func (o *Options) HasNotifierFile() bool {
+	return o.notifierFile != ""
+}
+
+func (o *Options) GetNotifierFile() string {
+	return o.notifierFile
+}
+
+func (o *Options) HasSMTPHost() bool {
+	return o.smtpHost != ""
+}
+
+func (o *Options) GetSMTPHost() string {
+	return o.smtpHost
+}
+
+func (o *Options) HasSMTPPort() bool {
+	return o.smtpPort != 0
+}
+
+func (o *Options) GetSMTPPort() int {
+	return o.smtpPort
+}
+
+func (o *Options) HasSMTPUsername() bool {
+	return o.smtpUsername != ""
+}
+
+func (o *Options) GetSMTPUsername() string {
+	return o.smtpUsername
+}
+
+func (o *Options) HasSMTPPassword() bool {
+	return o.smtpPassword != ""
+}
+
+func (o *Options) GetSMTPPassword() string {
+	return o.smtpPassword
+}
+
+func (o *Options) HasSMTPFrom() bool {
+	return o.smtpFrom != ""
+}
+
+func (o *Options) GetSMTPFrom() string {
+	return o.smtpFrom
+}
+
+func (o *Options) HasPasswordResetTTL() bool {
+	return o.passwordResetTTL != 0
+}
+
+func (o *Options) GetPasswordResetTTL() time.Duration {
+	return o.passwordResetTTL
+}
+
+func (o *Options) HasAdjustmentApprovalThreshold() bool {
+	return o.adjustmentApprovalThreshold != 0
+}
+
+func (o *Options) GetAdjustmentApprovalThreshold() float64 {
+	return o.adjustmentApprovalThreshold
+}
+
+func (o *Options) HasOIDCIssuer() bool {
+	return o.oidcIssuer != ""
+}
+
+func (o *Options) GetOIDCIssuer() string {
+	return o.oidcIssuer
+}
+
+func (o *Options) HasOIDCClientID() bool {
+	return o.oidcClientID != ""
+}
+
+func (o *Options) GetOIDCClientID() string {
+	return o.oidcClientID
+}
+
+func (o *Options) HasOIDCClientSecret() bool {
+	return o.oidcClientSecret != ""
+}
+
+func (o *Options) GetOIDCClientSecret() string {
+	return o.oidcClientSecret
+}
+
+func (o *Options) HasOIDCRedirectURL() bool {
+	return o.oidcRedirectURL != ""
+}
+
+func (o *Options) GetOIDCRedirectURL() string {
+	return o.oidcRedirectURL
+}
+
+func (o *Options) HasOIDCRoleClaim() bool {
+	return o.oidcRoleClaim != ""
+}
+
+func (o *Options) GetOIDCRoleClaim() string {
+	return o.oidcRoleClaim
+}
+
+func (o *Options) HasOIDCRoleMapping() bool {
+	return o.oidcRoleMapping != ""
+}
+
+func (o *Options) GetOIDCRoleMapping() string {
+	return o.oidcRoleMapping
+}
+
+func (o *Options) HasCookieSecure() bool {
+	return o.cookieSecure
+}
+
+func (o *Options) GetCookieSecure() bool {
+	return o.cookieSecure
+}
+
+func (o *Options) HasCookieSameSite() bool {
+	return o.cookieSameSite != ""
+}
+
+func (o *Options) GetCookieSameSite() string {
+	return o.cookieSameSite
+}
+
+func (o *Options) HasCookieDomain() bool {
+	return o.cookieDomain != ""
+}
+
+func (o *Options) GetCookieDomain() string {
+	return o.cookieDomain
+}
+
+func (o *Options) HasSessionRenewalThreshold() bool {
+	return o.sessionRenewalThreshold != 0
+}
+
+func (o *Options) GetSessionRenewalThreshold() time.Duration {
+	return o.sessionRenewalThreshold
+}
+
+func (o *Options) HasSessionMaxLifetime() bool {
+	return o.sessionMaxLifetime != 0
+}
+
+func (o *Options) GetSessionMaxLifetime() time.Duration {
+	return o.sessionMaxLifetime
+}
+
+func (o *Options) HasTLSCertFile() bool {
+	return o.tlsCertFile != ""
+}
+
+func (o *Options) GetTLSCertFile() string {
+	return o.tlsCertFile
+}
+
+func (o *Options) HasTLSKeyFile() bool {
+	return o.tlsKeyFile != ""
+}
+
+func (o *Options) GetTLSKeyFile() string {
+	return o.tlsKeyFile
+}
+
+func (o *Options) HasTLSClientCAFile() bool {
+	return o.tlsClientCAFile != ""
+}
+
+func (o *Options) GetTLSClientCAFile() string {
+	return o.tlsClientCAFile
+}
+
+func (o *Options) HasTLSMinVersion() bool {
+	return o.tlsMinVersion != ""
+}
+
+func (o *Options) GetTLSMinVersion() string {
+	return o.tlsMinVersion
+}
+
+func (o *Options) HasTLSCipherSuites() bool {
+	return o.tlsCipherSuites != ""
+}
+
+func (o *Options) GetTLSCipherSuites() string {
+	return o.tlsCipherSuites
+}
+
+func (o *Options) HasServerReadHeaderTimeout() bool {
+	return o.serverReadHeaderTimeout != 0
+}
+
+func (o *Options) GetServerReadHeaderTimeout() time.Duration {
+	return o.serverReadHeaderTimeout
+}
+
+func (o *Options) HasServerReadTimeout() bool {
+	return o.serverReadTimeout != 0
+}
+
+func (o *Options) GetServerReadTimeout() time.Duration {
+	return o.serverReadTimeout
+}
+
+func (o *Options) HasServerWriteTimeout() bool {
+	return o.serverWriteTimeout != 0
+}
+
+func (o *Options) GetServerWriteTimeout() time.Duration {
+	return o.serverWriteTimeout
+}
+
+func (o *Options) HasServerIdleTimeout() bool {
+	return o.serverIdleTimeout != 0
+}
+
+func (o *Options) GetServerIdleTimeout() time.Duration {
+	return o.serverIdleTimeout
+}
+
+func (o *Options) HasMaxRequestBodySize() bool {
+	return o.maxRequestBodySize != 0
+}
+
+func (o *Options) GetMaxRequestBodySize() int64 {
+	return o.maxRequestBodySize
+}
+
+func (o *Options) HasCrashReportSink() bool {
+	return o.crashReportSink != ""
+}
+
+func (o *Options) GetCrashReportSink() string {
+	return o.crashReportSink
+}
+
+func (o *Options) HasCrashReportFile() bool {
+	return o.crashReportFile != ""
+}
+
+func (o *Options) GetCrashReportFile() string {
+	return o.crashReportFile
+}
+
+func (o *Options) HasCompressionMinSize() bool {
+	return o.compressionMinSize != 0
+}
+
+func (o *Options) GetCompressionMinSize() int {
+	return o.compressionMinSize
+}
+
+func (o *Options) HasCompressionContentTypes() bool {
+	return o.compressionContentTypes != ""
+}
+
+func (o *Options) GetCompressionContentTypes() string {
+	return o.compressionContentTypes
+}
+
+func (o *Options) HasAppMode() bool {
+	return o.appMode != ""
+}
+
+func (o *Options) GetAppMode() string {
+	return o.appMode
+}
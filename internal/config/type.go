@@ -1,6 +1,75 @@
 package config
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	defaultLoginMaxAttempts     = 5
+	defaultLoginIPMaxAttempts   = 20
+	defaultLoginLockoutDuration = 15 * time.Minute
+
+	AppModeDevelopment = "development"
+	AppModeProduction  = "production"
+	defaultAppMode     = AppModeProduction
+
+	AuthTokenPrecedenceHeader = "header"
+	AuthTokenPrecedenceCookie = "cookie"
+
+	CookieSameSiteStrict  = "strict"
+	CookieSameSiteLax     = "lax"
+	CookieSameSiteNone    = "none"
+	defaultCookieSameSite = CookieSameSiteLax
+
+	TLSVersion12         = "1.2"
+	TLSVersion13         = "1.3"
+	defaultTLSMinVersion = TLSVersion12
+
+	PasswordHasherArgon2id = "argon2id"
+	PasswordHasherBcrypt   = "bcrypt"
+	DefaultPasswordHasher  = PasswordHasherArgon2id
+
+	defaultBcryptCost        = 12
+	defaultArgon2Memory      = 64 * 1024
+	defaultArgon2Iterations  = 3
+	defaultArgon2Parallelism = 4
+
+	// The Argon2 limits bound the work of a single password check. They also
+	// apply to the parameters read from stored hashes. Memory is in KiB.
+	MaxArgon2Memory      = 1024 * 1024
+	MaxArgon2Iterations  = 64
+	MaxArgon2Parallelism = math.MaxUint8
+
+	defaultPasswordMinLength  = 8
+	defaultPasswordMinClasses = 2
+
+	NotifierNone    = "none"
+	NotifierLog     = "log"
+	NotifierFile    = "file"
+	NotifierSMTP    = "smtp"
+	DefaultNotifier = NotifierNone
+
+	CrashReportSinkLog     = "log"
+	CrashReportSinkFile    = "file"
+	DefaultCrashReportSink = CrashReportSinkLog
+
+	defaultSMTPPort                    = 587
+	defaultAdjustmentApprovalThreshold = 1000.0
+	defaultOIDCRoleClaim               = "groups"
+	defaultPasswordResetTTL            = 30 * time.Minute
+	defaultSessionRenewalThreshold     = 5 * time.Minute
+	defaultSessionMaxLifetime          = 30 * 24 * time.Hour
+	defaultServerReadHeaderTimeout     = 5 * time.Second
+	defaultServerReadTimeout           = 30 * time.Second
+	defaultServerWriteTimeout          = 60 * time.Second
+	defaultServerIdleTimeout           = 120 * time.Second
+	defaultMaxRequestBodySize          = 1 << 20
+	defaultCompressionMinSize          = 1024
+	defaultCompressionContentTypes     = "application/json,application/problem+json,text/*,application/javascript,image/svg+xml"
+)
 
 type AppEnvironment interface {
 	isValid() bool
@@ -12,6 +81,102 @@ type AppEnvironment interface {
 	GetAccrualSystemURL() string
 	SetJWTSecret(secret string) error
 	GetJWTSecret() string
+	SetLoginMaxAttempts(attempts int) error
+	GetLoginMaxAttempts() int
+	SetLoginIPMaxAttempts(attempts int) error
+	GetLoginIPMaxAttempts() int
+	SetLoginLockoutDuration(d time.Duration) error
+	GetLoginLockoutDuration() time.Duration
+	SetAdminLogins(logins []string) error
+	GetAdminLogins() []string
+	SetAuthTokenPrecedence(precedence string) error
+	GetAuthTokenPrecedence() string
+	SetJWTKeysFile(path string) error
+	GetJWTKeysFile() string
+	SetPasswordHasher(algorithm string) error
+	GetPasswordHasher() string
+	SetBcryptCost(cost int) error
+	GetBcryptCost() int
+	SetArgon2Memory(memory int) error
+	GetArgon2Memory() int
+	SetArgon2Iterations(iterations int) error
+	GetArgon2Iterations() int
+	SetArgon2Parallelism(parallelism int) error
+	GetArgon2Parallelism() int
+	SetPasswordMinLength(length int) error
+	GetPasswordMinLength() int
+	SetPasswordMinClasses(classes int) error
+	GetPasswordMinClasses() int
+	SetNotifier(kind string) error
+	GetNotifier() string
+	SetNotifierFile(path string) error
+	GetNotifierFile() string
+	SetSMTPHost(host string) error
+	GetSMTPHost() string
+	SetSMTPPort(port int) error
+	GetSMTPPort() int
+	SetSMTPUsername(username string) error
+	GetSMTPUsername() string
+	SetSMTPPassword(password string) error
+	GetSMTPPassword() string
+	SetSMTPFrom(from string) error
+	GetSMTPFrom() string
+	SetPasswordResetTTL(ttl time.Duration) error
+	GetPasswordResetTTL() time.Duration
+	SetAdjustmentApprovalThreshold(threshold float64) error
+	GetAdjustmentApprovalThreshold() float64
+	SetOIDCIssuer(issuer string) error
+	GetOIDCIssuer() string
+	SetOIDCClientID(clientID string) error
+	GetOIDCClientID() string
+	SetOIDCClientSecret(secret string) error
+	GetOIDCClientSecret() string
+	SetOIDCRedirectURL(redirectURL string) error
+	GetOIDCRedirectURL() string
+	SetOIDCRoleClaim(claim string) error
+	GetOIDCRoleClaim() string
+	SetOIDCRoleMapping(mapping string) error
+	GetOIDCRoleMapping() string
+	SetCookieSecure(secure bool) error
+	GetCookieSecure() bool
+	SetCookieSameSite(sameSite string) error
+	GetCookieSameSite() string
+	SetCookieDomain(domain string) error
+	GetCookieDomain() string
+	SetSessionRenewalThreshold(threshold time.Duration) error
+	GetSessionRenewalThreshold() time.Duration
+	SetSessionMaxLifetime(lifetime time.Duration) error
+	GetSessionMaxLifetime() time.Duration
+	SetTLSCertFile(path string) error
+	GetTLSCertFile() string
+	SetTLSKeyFile(path string) error
+	GetTLSKeyFile() string
+	SetTLSClientCAFile(path string) error
+	GetTLSClientCAFile() string
+	SetTLSMinVersion(version string) error
+	GetTLSMinVersion() string
+	SetTLSCipherSuites(suites string) error
+	GetTLSCipherSuites() string
+	SetServerReadHeaderTimeout(timeout time.Duration) error
+	GetServerReadHeaderTimeout() time.Duration
+	SetServerReadTimeout(timeout time.Duration) error
+	GetServerReadTimeout() time.Duration
+	SetServerWriteTimeout(timeout time.Duration) error
+	GetServerWriteTimeout() time.Duration
+	SetServerIdleTimeout(timeout time.Duration) error
+	GetServerIdleTimeout() time.Duration
+	SetMaxRequestBodySize(size int64) error
+	GetMaxRequestBodySize() int64
+	SetCrashReportSink(sink string) error
+	GetCrashReportSink() string
+	SetCrashReportFile(path string) error
+	GetCrashReportFile() string
+	SetCompressionMinSize(size int) error
+	GetCompressionMinSize() int
+	SetCompressionContentTypes(types string) error
+	GetCompressionContentTypes() string
+	SetAppMode(mode string) error
+	GetAppMode() string
 }
 
 // todo переименовать перменные и методы
@@ -20,6 +185,55 @@ type Environment struct {
 	dbURI            string
 	accrualSystemURI string
 	jwtSecret        string
+
+	loginMaxAttempts            int
+	loginIPMaxAttempts          int
+	loginLockoutDuration        time.Duration
+	adminLogins                 []string
+	authTokenPrecedence         string
+	jwtKeysFile                 string
+	passwordHasher              string
+	bcryptCost                  int
+	argon2Memory                int
+	argon2Iterations            int
+	argon2Parallelism           int
+	passwordMinLength           int
+	passwordMinClasses          int
+	notifier                    string
+	notifierFile                string
+	smtpHost                    string
+	smtpPort                    int
+	smtpUsername                string
+	smtpPassword                string
+	smtpFrom                    string
+	passwordResetTTL            time.Duration
+	adjustmentApprovalThreshold float64
+	oidcIssuer                  string
+	oidcClientID                string
+	oidcClientSecret            string
+	oidcRedirectURL             string
+	oidcRoleClaim               string
+	oidcRoleMapping             string
+	cookieSecure                bool
+	cookieSameSite              string
+	cookieDomain                string
+	sessionRenewalThreshold     time.Duration
+	sessionMaxLifetime          time.Duration
+	tlsCertFile                 string
+	tlsKeyFile                  string
+	tlsClientCAFile             string
+	tlsMinVersion               string
+	tlsCipherSuites             string
+	serverReadHeaderTimeout     time.Duration
+	serverReadTimeout           time.Duration
+	serverWriteTimeout          time.Duration
+	serverIdleTimeout           time.Duration
+	maxRequestBodySize          int64
+	crashReportSink             string
+	crashReportFile             string
+	compressionMinSize          int
+	compressionContentTypes     string
+	appMode                     string
 }
 
 func (e *Environment) isValid() bool {
@@ -76,3 +290,741 @@ func (e *Environment) SetJWTSecret(secret string) error {
 func (e *Environment) GetJWTSecret() string {
 	return e.jwtSecret
 }
+
+func (e *Environment) SetLoginMaxAttempts(attempts int) error {
+	if attempts <= 0 {
+		return errors.New("fail set LoginMaxAttempts: value must be positive")
+	}
+
+	e.loginMaxAttempts = attempts
+	return nil
+}
+
+func (e *Environment) GetLoginMaxAttempts() int {
+	if e.loginMaxAttempts == 0 {
+		return defaultLoginMaxAttempts
+	}
+
+	return e.loginMaxAttempts
+}
+
+func (e *Environment) SetLoginIPMaxAttempts(attempts int) error {
+	if attempts <= 0 {
+		return errors.New("fail set LoginIPMaxAttempts: value must be positive")
+	}
+
+	e.loginIPMaxAttempts = attempts
+	return nil
+}
+
+func (e *Environment) GetLoginIPMaxAttempts() int {
+	if e.loginIPMaxAttempts == 0 {
+		return defaultLoginIPMaxAttempts
+	}
+
+	return e.loginIPMaxAttempts
+}
+
+func (e *Environment) SetLoginLockoutDuration(d time.Duration) error {
+	if d <= 0 {
+		return errors.New("fail set LoginLockoutDuration: value must be positive")
+	}
+
+	e.loginLockoutDuration = d
+	return nil
+}
+
+func (e *Environment) GetLoginLockoutDuration() time.Duration {
+	if e.loginLockoutDuration == 0 {
+		return defaultLoginLockoutDuration
+	}
+
+	return e.loginLockoutDuration
+}
+
+func (e *Environment) SetAdminLogins(logins []string) error {
+	if len(logins) == 0 {
+		return errors.New("fail set AdminLogins: value is empty")
+	}
+
+	e.adminLogins = logins
+	return nil
+}
+
+func (e *Environment) GetAdminLogins() []string {
+	return e.adminLogins
+}
+
+func (e *Environment) SetAuthTokenPrecedence(precedence string) error {
+	if precedence != AuthTokenPrecedenceHeader && precedence != AuthTokenPrecedenceCookie {
+		return errors.New("fail set AuthTokenPrecedence: value must be header or cookie")
+	}
+
+	e.authTokenPrecedence = precedence
+	return nil
+}
+
+func (e *Environment) GetAuthTokenPrecedence() string {
+	if e.authTokenPrecedence == "" {
+		return AuthTokenPrecedenceHeader
+	}
+
+	return e.authTokenPrecedence
+}
+
+func (e *Environment) SetJWTKeysFile(path string) error {
+	if path == "" {
+		return errors.New("fail set JWTKeysFile: value is empty")
+	}
+
+	e.jwtKeysFile = path
+	return nil
+}
+
+func (e *Environment) GetJWTKeysFile() string {
+	return e.jwtKeysFile
+}
+
+func (e *Environment) SetPasswordHasher(algorithm string) error {
+	if algorithm != PasswordHasherArgon2id && algorithm != PasswordHasherBcrypt {
+		return errors.New("fail set PasswordHasher: value must be argon2id or bcrypt")
+	}
+
+	e.passwordHasher = algorithm
+	return nil
+}
+
+func (e *Environment) GetPasswordHasher() string {
+	if e.passwordHasher == "" {
+		return DefaultPasswordHasher
+	}
+
+	return e.passwordHasher
+}
+
+func (e *Environment) SetBcryptCost(cost int) error {
+	if cost <= 0 {
+		return errors.New("fail set BcryptCost: value must be positive")
+	}
+
+	e.bcryptCost = cost
+	return nil
+}
+
+func (e *Environment) GetBcryptCost() int {
+	if e.bcryptCost == 0 {
+		return defaultBcryptCost
+	}
+
+	return e.bcryptCost
+}
+
+func (e *Environment) SetArgon2Memory(memory int) error {
+	if memory <= 0 || memory > MaxArgon2Memory {
+		return fmt.Errorf("fail set Argon2Memory: value must be between 1 and %d", MaxArgon2Memory)
+	}
+
+	e.argon2Memory = memory
+	return nil
+}
+
+func (e *Environment) GetArgon2Memory() int {
+	if e.argon2Memory == 0 {
+		return defaultArgon2Memory
+	}
+
+	return e.argon2Memory
+}
+
+func (e *Environment) SetArgon2Iterations(iterations int) error {
+	if iterations <= 0 || iterations > MaxArgon2Iterations {
+		return fmt.Errorf("fail set Argon2Iterations: value must be between 1 and %d", MaxArgon2Iterations)
+	}
+
+	e.argon2Iterations = iterations
+	return nil
+}
+
+func (e *Environment) GetArgon2Iterations() int {
+	if e.argon2Iterations == 0 {
+		return defaultArgon2Iterations
+	}
+
+	return e.argon2Iterations
+}
+
+func (e *Environment) SetArgon2Parallelism(parallelism int) error {
+	if parallelism <= 0 || parallelism > MaxArgon2Parallelism {
+		return fmt.Errorf("fail set Argon2Parallelism: value must be between 1 and %d", MaxArgon2Parallelism)
+	}
+
+	e.argon2Parallelism = parallelism
+	return nil
+}
+
+func (e *Environment) GetArgon2Parallelism() int {
+	if e.argon2Parallelism == 0 {
+		return defaultArgon2Parallelism
+	}
+
+	return e.argon2Parallelism
+}
+
+func (e *Environment) SetPasswordMinLength(length int) error {
+	if length <= 0 {
+		return errors.New("fail set PasswordMinLength: value must be positive")
+	}
+
+	e.passwordMinLength = length
+	return nil
+}
+
+func (e *Environment) GetPasswordMinLength() int {
+	if e.passwordMinLength == 0 {
+		return defaultPasswordMinLength
+	}
+
+	return e.passwordMinLength
+}
+
+func (e *Environment) SetPasswordMinClasses(classes int) error {
+	if classes <= 0 {
+		return errors.New("fail set PasswordMinClasses: value must be positive")
+	}
+
+	e.passwordMinClasses = classes
+	return nil
+}
+
+func (e *Environment) GetPasswordMinClasses() int {
+	if e.passwordMinClasses == 0 {
+		return defaultPasswordMinClasses
+	}
+
+	return e.passwordMinClasses
+}
+
+func (e *Environment) SetNotifier(kind string) error {
+	// The value is checked by notifier.Init, so a mistyped notifier stops
+	// the start instead of falling back to the default.
+	if kind == "" {
+		return errors.New("fail set Notifier: value is empty")
+	}
+
+	e.notifier = kind
+	return nil
+}
+
+func (e *Environment) GetNotifier() string {
+	if e.notifier == "" {
+		return DefaultNotifier
+	}
+
+	return e.notifier
+}
+
+func (e *Environment) SetNotifierFile(path string) error {
+	if path == "" {
+		return errors.New("fail set NotifierFile: value is empty")
+	}
+
+	e.notifierFile = path
+	return nil
+}
+
+func (e *Environment) GetNotifierFile() string {
+	return e.notifierFile
+}
+
+func (e *Environment) SetSMTPHost(host string) error {
+	if host == "" {
+		return errors.New("fail set SMTPHost: value is empty")
+	}
+
+	e.smtpHost = host
+	return nil
+}
+
+func (e *Environment) GetSMTPHost() string {
+	return e.smtpHost
+}
+
+func (e *Environment) SetSMTPPort(port int) error {
+	if port <= 0 {
+		return errors.New("fail set SMTPPort: value must be positive")
+	}
+
+	e.smtpPort = port
+	return nil
+}
+
+func (e *Environment) GetSMTPPort() int {
+	if e.smtpPort == 0 {
+		return defaultSMTPPort
+	}
+
+	return e.smtpPort
+}
+
+func (e *Environment) SetSMTPUsername(username string) error {
+	if username == "" {
+		return errors.New("fail set SMTPUsername: value is empty")
+	}
+
+	e.smtpUsername = username
+	return nil
+}
+
+func (e *Environment) GetSMTPUsername() string {
+	return e.smtpUsername
+}
+
+func (e *Environment) SetSMTPPassword(password string) error {
+	if password == "" {
+		return errors.New("fail set SMTPPassword: value is empty")
+	}
+
+	e.smtpPassword = password
+	return nil
+}
+
+func (e *Environment) GetSMTPPassword() string {
+	return e.smtpPassword
+}
+
+func (e *Environment) SetSMTPFrom(from string) error {
+	if from == "" {
+		return errors.New("fail set SMTPFrom: value is empty")
+	}
+
+	e.smtpFrom = from
+	return nil
+}
+
+func (e *Environment) GetSMTPFrom() string {
+	return e.smtpFrom
+}
+
+func (e *Environment) SetPasswordResetTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.New("fail set PasswordResetTTL: value must be positive")
+	}
+
+	e.passwordResetTTL = ttl
+	return nil
+}
+
+func (e *Environment) GetPasswordResetTTL() time.Duration {
+	if e.passwordResetTTL == 0 {
+		return defaultPasswordResetTTL
+	}
+
+	return e.passwordResetTTL
+}
+
+func (e *Environment) SetAdjustmentApprovalThreshold(threshold float64) error {
+	if threshold <= 0 {
+		return errors.New("fail set AdjustmentApprovalThreshold: value must be positive")
+	}
+
+	e.adjustmentApprovalThreshold = threshold
+	return nil
+}
+
+func (e *Environment) GetAdjustmentApprovalThreshold() float64 {
+	if e.adjustmentApprovalThreshold == 0 {
+		return defaultAdjustmentApprovalThreshold
+	}
+
+	return e.adjustmentApprovalThreshold
+}
+
+func (e *Environment) SetOIDCIssuer(issuer string) error {
+	if issuer == "" {
+		return errors.New("fail set OIDCIssuer: value is empty")
+	}
+
+	e.oidcIssuer = issuer
+	return nil
+}
+
+func (e *Environment) GetOIDCIssuer() string {
+	return e.oidcIssuer
+}
+
+func (e *Environment) SetOIDCClientID(clientID string) error {
+	if clientID == "" {
+		return errors.New("fail set OIDCClientID: value is empty")
+	}
+
+	e.oidcClientID = clientID
+	return nil
+}
+
+func (e *Environment) GetOIDCClientID() string {
+	return e.oidcClientID
+}
+
+func (e *Environment) SetOIDCClientSecret(secret string) error {
+	if secret == "" {
+		return errors.New("fail set OIDCClientSecret: value is empty")
+	}
+
+	e.oidcClientSecret = secret
+	return nil
+}
+
+func (e *Environment) GetOIDCClientSecret() string {
+	return e.oidcClientSecret
+}
+
+func (e *Environment) SetOIDCRedirectURL(redirectURL string) error {
+	if redirectURL == "" {
+		return errors.New("fail set OIDCRedirectURL: value is empty")
+	}
+
+	e.oidcRedirectURL = redirectURL
+	return nil
+}
+
+func (e *Environment) GetOIDCRedirectURL() string {
+	return e.oidcRedirectURL
+}
+
+func (e *Environment) SetOIDCRoleClaim(claim string) error {
+	if claim == "" {
+		return errors.New("fail set OIDCRoleClaim: value is empty")
+	}
+
+	e.oidcRoleClaim = claim
+	return nil
+}
+
+func (e *Environment) GetOIDCRoleClaim() string {
+	if e.oidcRoleClaim == "" {
+		return defaultOIDCRoleClaim
+	}
+
+	return e.oidcRoleClaim
+}
+
+func (e *Environment) SetOIDCRoleMapping(mapping string) error {
+	if mapping == "" {
+		return errors.New("fail set OIDCRoleMapping: value is empty")
+	}
+
+	e.oidcRoleMapping = mapping
+	return nil
+}
+
+func (e *Environment) GetOIDCRoleMapping() string {
+	return e.oidcRoleMapping
+}
+
+func (e *Environment) SetCookieSecure(secure bool) error {
+	e.cookieSecure = secure
+	return nil
+}
+
+func (e *Environment) GetCookieSecure() bool {
+	return e.cookieSecure
+}
+
+func (e *Environment) SetCookieSameSite(sameSite string) error {
+	if sameSite != CookieSameSiteStrict && sameSite != CookieSameSiteLax && sameSite != CookieSameSiteNone {
+		return errors.New("fail set CookieSameSite: value must be strict, lax or none")
+	}
+
+	e.cookieSameSite = sameSite
+	return nil
+}
+
+func (e *Environment) GetCookieSameSite() string {
+	if e.cookieSameSite == "" {
+		return defaultCookieSameSite
+	}
+
+	return e.cookieSameSite
+}
+
+func (e *Environment) SetCookieDomain(domain string) error {
+	if domain == "" {
+		return errors.New("fail set CookieDomain: value is empty")
+	}
+
+	e.cookieDomain = domain
+	return nil
+}
+
+func (e *Environment) GetCookieDomain() string {
+	return e.cookieDomain
+}
+
+func (e *Environment) SetSessionRenewalThreshold(threshold time.Duration) error {
+	if threshold <= 0 {
+		return errors.New("fail set SessionRenewalThreshold: value must be positive")
+	}
+
+	e.sessionRenewalThreshold = threshold
+	return nil
+}
+
+func (e *Environment) GetSessionRenewalThreshold() time.Duration {
+	if e.sessionRenewalThreshold == 0 {
+		return defaultSessionRenewalThreshold
+	}
+
+	return e.sessionRenewalThreshold
+}
+
+func (e *Environment) SetSessionMaxLifetime(lifetime time.Duration) error {
+	if lifetime <= 0 {
+		return errors.New("fail set SessionMaxLifetime: value must be positive")
+	}
+
+	e.sessionMaxLifetime = lifetime
+	return nil
+}
+
+func (e *Environment) GetSessionMaxLifetime() time.Duration {
+	if e.sessionMaxLifetime == 0 {
+		return defaultSessionMaxLifetime
+	}
+
+	return e.sessionMaxLifetime
+}
+
+func (e *Environment) SetTLSCertFile(path string) error {
+	if path == "" {
+		return errors.New("fail set TLSCertFile: value is empty")
+	}
+
+	e.tlsCertFile = path
+	return nil
+}
+
+func (e *Environment) GetTLSCertFile() string {
+	return e.tlsCertFile
+}
+
+func (e *Environment) SetTLSKeyFile(path string) error {
+	if path == "" {
+		return errors.New("fail set TLSKeyFile: value is empty")
+	}
+
+	e.tlsKeyFile = path
+	return nil
+}
+
+func (e *Environment) GetTLSKeyFile() string {
+	return e.tlsKeyFile
+}
+
+func (e *Environment) SetTLSClientCAFile(path string) error {
+	if path == "" {
+		return errors.New("fail set TLSClientCAFile: value is empty")
+	}
+
+	e.tlsClientCAFile = path
+	return nil
+}
+
+func (e *Environment) GetTLSClientCAFile() string {
+	return e.tlsClientCAFile
+}
+
+func (e *Environment) SetTLSMinVersion(version string) error {
+	if version != TLSVersion12 && version != TLSVersion13 {
+		return errors.New("fail set TLSMinVersion: value must be 1.2 or 1.3")
+	}
+
+	e.tlsMinVersion = version
+	return nil
+}
+
+func (e *Environment) GetTLSMinVersion() string {
+	if e.tlsMinVersion == "" {
+		return defaultTLSMinVersion
+	}
+
+	return e.tlsMinVersion
+}
+
+func (e *Environment) SetTLSCipherSuites(suites string) error {
+	if suites == "" {
+		return errors.New("fail set TLSCipherSuites: value is empty")
+	}
+
+	e.tlsCipherSuites = suites
+	return nil
+}
+
+func (e *Environment) GetTLSCipherSuites() string {
+	return e.tlsCipherSuites
+}
+
+func (e *Environment) SetServerReadHeaderTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return errors.New("fail set ServerReadHeaderTimeout: value must be positive")
+	}
+
+	e.serverReadHeaderTimeout = timeout
+	return nil
+}
+
+func (e *Environment) GetServerReadHeaderTimeout() time.Duration {
+	if e.serverReadHeaderTimeout == 0 {
+		return defaultServerReadHeaderTimeout
+	}
+
+	return e.serverReadHeaderTimeout
+}
+
+func (e *Environment) SetServerReadTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return errors.New("fail set ServerReadTimeout: value must be positive")
+	}
+
+	e.serverReadTimeout = timeout
+	return nil
+}
+
+func (e *Environment) GetServerReadTimeout() time.Duration {
+	if e.serverReadTimeout == 0 {
+		return defaultServerReadTimeout
+	}
+
+	return e.serverReadTimeout
+}
+
+func (e *Environment) SetServerWriteTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return errors.New("fail set ServerWriteTimeout: value must be positive")
+	}
+
+	e.serverWriteTimeout = timeout
+	return nil
+}
+
+func (e *Environment) GetServerWriteTimeout() time.Duration {
+	if e.serverWriteTimeout == 0 {
+		return defaultServerWriteTimeout
+	}
+
+	return e.serverWriteTimeout
+}
+
+func (e *Environment) SetServerIdleTimeout(timeout time.Duration) error {
+	if timeout <= 0 {
+		return errors.New("fail set ServerIdleTimeout: value must be positive")
+	}
+
+	e.serverIdleTimeout = timeout
+	return nil
+}
+
+func (e *Environment) GetServerIdleTimeout() time.Duration {
+	if e.serverIdleTimeout == 0 {
+		return defaultServerIdleTimeout
+	}
+
+	return e.serverIdleTimeout
+}
+
+func (e *Environment) SetMaxRequestBodySize(size int64) error {
+	if size <= 0 {
+		return errors.New("fail set MaxRequestBodySize: value must be positive")
+	}
+
+	e.maxRequestBodySize = size
+	return nil
+}
+
+func (e *Environment) GetMaxRequestBodySize() int64 {
+	if e.maxRequestBodySize == 0 {
+		return defaultMaxRequestBodySize
+	}
+
+	return e.maxRequestBodySize
+}
+
+func (e *Environment) SetCrashReportSink(sink string) error {
+	if sink != CrashReportSinkLog && sink != CrashReportSinkFile {
+		return errors.New("fail set CrashReportSink: value must be log or file")
+	}
+
+	e.crashReportSink = sink
+	return nil
+}
+
+func (e *Environment) GetCrashReportSink() string {
+	if e.crashReportSink == "" {
+		return DefaultCrashReportSink
+	}
+
+	return e.crashReportSink
+}
+
+func (e *Environment) SetCrashReportFile(path string) error {
+	if path == "" {
+		return errors.New("fail set CrashReportFile: value is empty")
+	}
+
+	e.crashReportFile = path
+	return nil
+}
+
+func (e *Environment) GetCrashReportFile() string {
+	return e.crashReportFile
+}
+
+func (e *Environment) SetCompressionMinSize(size int) error {
+	if size <= 0 {
+		return errors.New("fail set CompressionMinSize: value must be positive")
+	}
+
+	e.compressionMinSize = size
+	return nil
+}
+
+func (e *Environment) GetCompressionMinSize() int {
+	if e.compressionMinSize == 0 {
+		return defaultCompressionMinSize
+	}
+
+	return e.compressionMinSize
+}
+
+func (e *Environment) SetCompressionContentTypes(types string) error {
+	if types == "" {
+		return errors.New("fail set CompressionContentTypes: value is empty")
+	}
+
+	e.compressionContentTypes = types
+	return nil
+}
+
+func (e *Environment) GetCompressionContentTypes() string {
+	if e.compressionContentTypes == "" {
+		return defaultCompressionContentTypes
+	}
+
+	return e.compressionContentTypes
+}
+
+func (e *Environment) SetAppMode(mode string) error {
+	if mode != AppModeDevelopment && mode != AppModeProduction {
+		return errors.New("fail set AppMode: value must be development or production")
+	}
+
+	e.appMode = mode
+	return nil
+}
+
+func (e *Environment) GetAppMode() string {
+	if e.appMode == "" {
+		return defaultAppMode
+	}
+
+	return e.appMode
+}
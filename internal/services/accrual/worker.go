@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/crash"
 	"github.com/MagicNetLab/go-diploma/internal/services/logger"
 	"github.com/go-resty/resty/v2"
 )
@@ -24,7 +25,7 @@ func RunWorker() {
 	httpc = resty.New().SetBaseURL(serviceHost)
 
 	for i := 0; i < queueSize; i++ {
-		go worker()
+		crash.Go("accrual_worker", worker)
 	}
 }
 
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+type principalKey struct{}
+
+// Chain tries authenticators in order and returns the first principal found.
+type Chain []Authenticator
+
+func (c Chain) Authenticate(r *http.Request) (Principal, error) {
+	for _, a := range c {
+		p, err := a.Authenticate(r)
+		if errors.Is(err, ErrorNoCredentials) {
+			continue
+		}
+
+		return p, err
+	}
+
+	return Principal{}, ErrorNoCredentials
+}
+
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// UserID returns the authenticated user of the request context.
+func UserID(ctx context.Context) (int, error) {
+	p, ok := FromContext(ctx)
+	if !ok || p.UserID == 0 {
+		return 0, ErrorNoPrincipal
+	}
+
+	return p.UserID, nil
+}
+
+// IsValidRole reports whether the role is one of the known user roles.
+func IsValidRole(role string) bool {
+	return role == RoleUser || role == RoleSupport || role == RoleAdmin
+}
+
+// ImpliedRoles expands the user role into all roles it grants: support staff
+// can do everything a user can, and admins everything support can.
+func ImpliedRoles(role string) []string {
+	switch role {
+	case RoleAdmin:
+		return []string{RoleUser, RoleSupport, RoleAdmin}
+	case RoleSupport:
+		return []string{RoleUser, RoleSupport}
+	default:
+		return []string{RoleUser}
+	}
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+const (
+	TokenCookie = "token"
+	CSRFCookie  = "csrf_token"
+	CSRFHeader  = "X-CSRF-Token"
+
+	// HostCookiePrefix and SecureCookiePrefix make browsers reject the cookie
+	// unless it is Secure (and for __Host- also host-only with path "/"), so
+	// it cannot be planted by a sibling subdomain or over plain HTTP.
+	HostCookiePrefix   = "__Host-"
+	SecureCookiePrefix = "__Secure-"
+)
+
+// Cookie returns the named cookie, preferring its prefixed variants.
+func Cookie(r *http.Request, name string) (*http.Cookie, error) {
+	for _, prefix := range []string{HostCookiePrefix, SecureCookiePrefix} {
+		if cookie, err := r.Cookie(prefix + name); err == nil {
+			return cookie, nil
+		}
+	}
+
+	return r.Cookie(name)
+}
+
+// ValidCSRF checks the double-submit token: the header must repeat the value
+// of the CSRF cookie, which other sites can neither read nor set.
+func ValidCSRF(r *http.Request) bool {
+	cookie, err := Cookie(r, CSRFCookie)
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+
+	header := r.Header.Get(CSRFHeader)
+	if header == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(header)) == 1
+}
+
+// IsSafeMethod reports whether the method does not change state and so does
+// not need CSRF protection.
+func IsSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
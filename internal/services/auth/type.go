@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+const (
+	RoleUser    = "user"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+
+	CredentialCookie = "cookie"
+	CredentialBearer = "bearer"
+	CredentialAPIKey = "api_key"
+
+	ScopeOrdersRead      = "orders:read"
+	ScopeOrdersWrite     = "orders:write"
+	ScopeBalanceRead     = "balance:read"
+	ScopeBalanceWithdraw = "balance:withdraw"
+)
+
+// Scopes lists the scopes an API key can be issued with.
+var Scopes = []string{ScopeOrdersRead, ScopeOrdersWrite, ScopeBalanceRead, ScopeBalanceWithdraw}
+
+// ReadScopes lists the scopes which do not let a key change anything. Only
+// these can be granted by staff to a key of another user.
+var ReadScopes = []string{ScopeOrdersRead, ScopeBalanceRead}
+
+var ErrorNoCredentials = errors.New("no credentials in request")
+var ErrorNoPrincipal = errors.New("request is not authenticated")
+
+// Authenticator verifies one kind of credential carried by a request.
+// It returns ErrorNoCredentials when the request has no credential of its kind,
+// so the next authenticator of a Chain can try.
+type Authenticator interface {
+	Authenticate(r *http.Request) (Principal, error)
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserID     int
+	Roles      []string
+	TokenID    string
+	SessionID  string
+	Credential string
+	ExpiresAt  time.Time
+	// Scopes limit what an API key may do. Principals of user sessions are
+	// not limited by scopes.
+	Scopes []string
+}
+
+func (p Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p Principal) HasScope(scope string) bool {
+	if p.Credential != CredentialAPIKey {
+		return true
+	}
+
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,94 @@
+package compression
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+)
+
+// Init applies the configured size threshold and content type allowlist.
+func Init(env config.AppEnvironment) {
+	minSize = env.GetCompressionMinSize()
+
+	var types []string
+	for _, t := range strings.Split(env.GetCompressionContentTypes(), ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			types = append(types, t)
+		}
+	}
+	contentTypes = types
+}
+
+// Middleware decodes gzip, brotli and zstd request bodies and compresses
+// responses with the best encoding the client accepts. Small responses and
+// content types outside the allowlist are sent as is.
+func Middleware(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := decodeBody(w, r)
+		if !ok {
+			return
+		}
+		if body != nil {
+			defer body.Close()
+		}
+
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiate(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		// After a panic nothing buffered is sent, so the recovery middleware
+		// can still answer with an error.
+		cw := &compressWriter{w: w, encoding: encoding}
+		completed := false
+		defer func() {
+			if completed {
+				cw.Close()
+			} else {
+				cw.discard()
+			}
+		}()
+
+		h.ServeHTTP(cw, r)
+		completed = true
+	}
+}
+
+// decodeBody replaces the request body with a decoder of its content
+// encoding, which the caller must close to return the decoder to its pool.
+// It answers the request itself and returns false when the encoding is not
+// supported or the body is not valid.
+func decodeBody(w http.ResponseWriter, r *http.Request) (io.Closer, bool) {
+	encoding := strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding")))
+	switch encoding {
+	case "", EncodingIdentity:
+		return nil, true
+	case EncodingGzip, EncodingBrotli, EncodingZstd:
+	default:
+		w.Header().Set("Accept-Encoding", strings.Join(append([]string{EncodingIdentity}, preferred...), ", "))
+		http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+		return nil, false
+	}
+
+	d, err := getDecoder(encoding, r.Body)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "encoding": encoding}
+		logger.Error("failed decode request body", args)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return nil, false
+	}
+
+	body := &decodingBody{body: r.Body, decoder: d, encoding: encoding}
+	r.Body = body
+	r.Header.Del("Content-Encoding")
+	r.Header.Del("Content-Length")
+	r.ContentLength = -1
+
+	return body, true
+}
@@ -0,0 +1,95 @@
+package compression
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// negotiate picks the response encoding from the Accept-Encoding header
+// (RFC 9110, section 12.5.3). It returns an empty string when the response
+// must not be compressed.
+func negotiate(acceptEncoding string) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	qualities := make(map[string]float64)
+	wildcard := -1.0
+	for _, item := range strings.Split(acceptEncoding, ",") {
+		coding, q, ok := parseCoding(item)
+		if !ok {
+			continue
+		}
+
+		if coding == "*" {
+			wildcard = q
+			continue
+		}
+		qualities[coding] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range preferred {
+		q, ok := qualities[encoding]
+		if !ok {
+			q = wildcard
+		}
+
+		if q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+
+	// An explicitly preferred identity wins over the encodings.
+	if q, ok := qualities[EncodingIdentity]; ok && q > bestQ {
+		return ""
+	}
+
+	return best
+}
+
+// parseCoding parses one "coding;q=0.5" element.
+func parseCoding(item string) (string, float64, bool) {
+	coding, params, _ := strings.Cut(item, ";")
+	coding = strings.ToLower(strings.TrimSpace(coding))
+	if coding == "" {
+		return "", 0, false
+	}
+
+	q := 1.0
+	for _, param := range strings.Split(params, ";") {
+		name, value, found := strings.Cut(param, "=")
+		if !found || strings.ToLower(strings.TrimSpace(name)) != "q" {
+			continue
+		}
+
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || parsed < 0 || parsed > 1 {
+			return "", 0, false
+		}
+		q = parsed
+	}
+
+	return coding, q, true
+}
+
+// compressible reports whether responses of the content type are compressed.
+func compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, allowed := range contentTypes {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(mediaType, prefix) {
+				return true
+			}
+		} else if mediaType == allowed {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package compression
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+var encoders = map[string]*encoderPool{
+	EncodingGzip: {pool: sync.Pool{New: func() interface{} {
+		return gzip.NewWriter(nil)
+	}}},
+	EncodingBrotli: {pool: sync.Pool{New: func() interface{} {
+		return brotli.NewWriterLevel(nil, brotliLevel)
+	}}},
+	EncodingZstd: {pool: sync.Pool{New: func() interface{} {
+		e, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+		return e
+	}}},
+}
+
+var decoders = map[string]*sync.Pool{
+	EncodingGzip:   {},
+	EncodingBrotli: {},
+	EncodingZstd:   {},
+}
+
+// getDecoder returns a pooled decoder reading from r. Pools are filled as
+// decoders are returned, since a gzip reader cannot be created without input.
+func getDecoder(encoding string, r io.Reader) (decoder, error) {
+	if d, ok := decoders[encoding].Get().(decoder); ok {
+		if err := d.Reset(r); err != nil {
+			return nil, err
+		}
+
+		return d, nil
+	}
+
+	switch encoding {
+	case EncodingGzip:
+		return gzip.NewReader(r)
+	case EncodingBrotli:
+		return brotli.NewReader(r), nil
+	default:
+		return zstd.NewReader(r, zstd.WithDecoderConcurrency(1), zstd.WithDecoderMaxWindow(zstdMaxWindow))
+	}
+}
+
+func putDecoder(encoding string, d decoder) {
+	decoders[encoding].Put(d)
+}
@@ -0,0 +1,54 @@
+package compression
+
+import (
+	"io"
+	"sync"
+)
+
+const (
+	EncodingBrotli   = "br"
+	EncodingZstd     = "zstd"
+	EncodingGzip     = "gzip"
+	EncodingIdentity = "identity"
+
+	brotliLevel = 4
+	// zstdMaxWindow bounds the memory a request body decoder may allocate.
+	zstdMaxWindow = 8 << 20
+)
+
+// preferred lists supported response encodings in the order the server
+// prefers them when the client accepts several with the same q-value.
+var preferred = []string{EncodingBrotli, EncodingZstd, EncodingGzip}
+
+var (
+	minSize      = 1024
+	contentTypes = []string{"application/json", "application/problem+json", "text/*"}
+)
+
+// encoder is implemented by the gzip, brotli and zstd writers, which can all
+// be reset to a new destination and so reused through a pool.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// decoder is implemented by the gzip, brotli and zstd readers.
+type decoder interface {
+	io.Reader
+	Reset(r io.Reader) error
+}
+
+type encoderPool struct {
+	pool sync.Pool
+}
+
+func (p *encoderPool) get(w io.Writer) encoder {
+	e := p.pool.Get().(encoder)
+	e.Reset(w)
+	return e
+}
+
+func (p *encoderPool) put(e encoder) {
+	p.pool.Put(e)
+}
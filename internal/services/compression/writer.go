@@ -0,0 +1,185 @@
+package compression
+
+import (
+	"io"
+	"net/http"
+)
+
+// compressWriter buffers the beginning of the response until it knows
+// whether compression pays off: the response must reach the size threshold
+// and have an allowed content type. Headers are sent only after that.
+type compressWriter struct {
+	w        http.ResponseWriter
+	encoding string
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     encoder
+}
+
+func (c *compressWriter) Header() http.Header {
+	return c.w.Header()
+}
+
+func (c *compressWriter) WriteHeader(statusCode int) {
+	if c.decided || c.status != 0 {
+		return
+	}
+
+	c.status = statusCode
+	if !bodyAllowed(statusCode) {
+		c.start(false)
+	}
+}
+
+func (c *compressWriter) Write(p []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+
+	if !c.decided {
+		c.buf = append(c.buf, p...)
+		if len(c.buf) < minSize {
+			return len(p), nil
+		}
+
+		if err := c.start(true); err != nil {
+			return 0, err
+		}
+
+		return len(p), nil
+	}
+
+	if c.enc != nil {
+		return c.enc.Write(p)
+	}
+
+	return c.w.Write(p)
+}
+
+// Flush sends the buffered data, so streamed responses are compressed even
+// before they reach the size threshold.
+func (c *compressWriter) Flush() {
+	if !c.decided {
+		if c.status == 0 && len(c.buf) == 0 {
+			return
+		}
+		if c.status == 0 {
+			c.status = http.StatusOK
+		}
+		c.start(true)
+	}
+
+	if c.enc != nil {
+		c.enc.Flush()
+	}
+
+	if f, ok := c.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (c *compressWriter) Unwrap() http.ResponseWriter {
+	return c.w
+}
+
+// Close completes the response. A response which never reached the
+// threshold is sent uncompressed, and nothing is sent when the handler wrote
+// nothing at all.
+func (c *compressWriter) Close() error {
+	if !c.decided {
+		if c.status == 0 {
+			return nil
+		}
+
+		if err := c.start(false); err != nil {
+			return err
+		}
+	}
+
+	if c.enc == nil {
+		return nil
+	}
+
+	err := c.enc.Close()
+	encoders[c.encoding].put(c.enc)
+	c.enc = nil
+
+	return err
+}
+
+// discard drops the buffered data without sending anything.
+func (c *compressWriter) discard() {
+	c.buf = nil
+	if c.enc != nil {
+		encoders[c.encoding].put(c.enc)
+		c.enc = nil
+	}
+}
+
+// start sends the headers and the buffered data, compressing them when
+// compress is set and the response qualifies.
+func (c *compressWriter) start(compress bool) error {
+	c.decided = true
+
+	h := c.w.Header()
+	if h.Get("Content-Type") == "" && len(c.buf) > 0 {
+		h.Set("Content-Type", http.DetectContentType(c.buf))
+	}
+
+	if compress && bodyAllowed(c.status) && h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type")) {
+		h.Set("Content-Encoding", c.encoding)
+		h.Del("Content-Length")
+		// Strong validators describe the identity representation.
+		if etag := h.Get("ETag"); etag != "" && etag[0] == '"' {
+			h.Set("ETag", "W/"+etag)
+		}
+		c.enc = encoders[c.encoding].get(c.w)
+	}
+
+	c.w.WriteHeader(c.status)
+
+	buf := c.buf
+	c.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+
+	var w io.Writer = c.w
+	if c.enc != nil {
+		w = c.enc
+	}
+	_, err := w.Write(buf)
+
+	return err
+}
+
+func bodyAllowed(status int) bool {
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// decodingBody closes the original body and returns the decoder to its pool.
+type decodingBody struct {
+	body     io.ReadCloser
+	decoder  decoder
+	encoding string
+}
+
+func (b *decodingBody) Read(p []byte) (int, error) {
+	if b.decoder == nil {
+		return 0, http.ErrBodyReadAfterClose
+	}
+
+	return b.decoder.Read(p)
+}
+
+func (b *decodingBody) Close() error {
+	if b.decoder != nil {
+		putDecoder(b.encoding, b.decoder)
+		b.decoder = nil
+	}
+
+	return b.body.Close()
+}
@@ -0,0 +1,88 @@
+package crash
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+)
+
+var sink Sink = &LogSink{}
+
+// Init selects the crash report sink configured by CRASH_REPORT_SINK.
+func Init(env config.AppEnvironment) error {
+	switch env.GetCrashReportSink() {
+	case config.CrashReportSinkFile:
+		if env.GetCrashReportFile() == "" {
+			return errors.New("file crash report sink requires CRASH_REPORT_FILE")
+		}
+
+		sink = NewFileSink(env.GetCrashReportFile())
+	default:
+		sink = &LogSink{}
+	}
+
+	return nil
+}
+
+// Record counts the panic and writes the crash report. It must be called from
+// the deferred function which recovered the panic, so the stack is still the
+// stack of the panicking goroutine.
+func Record(report Report, recovered interface{}) {
+	report.Time = time.Now()
+	report.Panic = fmt.Sprint(recovered)
+	report.Stack = string(debug.Stack())
+
+	Panics.Add(report.Source, 1)
+
+	if err := sink.Write(report); err != nil {
+		args := map[string]interface{}{"error": err.Error(), "source": report.Source, "panic": report.Panic}
+		logger.Error("failed write crash report", args)
+	}
+}
+
+// Go runs the worker in a supervised goroutine: a panic is recorded and the
+// worker is started again after a growing delay. The supervisor stops when
+// the worker returns normally.
+func Go(name string, worker func()) {
+	go func() {
+		delay := restartBaseDelay
+		for {
+			started := time.Now()
+			if !run(name, worker) {
+				return
+			}
+
+			if time.Since(started) > stableRunTime {
+				delay = restartBaseDelay
+			}
+
+			Restarts.Add(name, 1)
+			args := map[string]interface{}{"worker": name, "delay": delay}
+			logger.Error("worker panicked, restarting", args)
+
+			time.Sleep(delay)
+			delay *= 2
+			if delay > restartMaxDelay {
+				delay = restartMaxDelay
+			}
+		}
+	}()
+}
+
+// run reports whether the worker panicked.
+func run(name string, worker func()) (panicked bool) {
+	defer func() {
+		if v := recover(); v != nil {
+			Record(Report{Source: name}, v)
+			panicked = true
+		}
+	}()
+
+	worker()
+
+	return false
+}
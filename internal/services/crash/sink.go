@@ -0,0 +1,56 @@
+package crash
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+)
+
+// LogSink writes crash reports to the application log.
+type LogSink struct{}
+
+func (s *LogSink) Write(report Report) error {
+	args := map[string]interface{}{
+		"source":     report.Source,
+		"request_id": report.RequestID,
+		"method":     report.Method,
+		"path":       report.Path,
+		"panic":      report.Panic,
+		"stack":      report.Stack,
+	}
+	logger.Error("crash report", args)
+
+	return nil
+}
+
+// FileSink appends crash reports to a file, one JSON document per line.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Write(report Report) error {
+	line, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+
+	return err
+}
@@ -0,0 +1,36 @@
+package crash
+
+import (
+	"expvar"
+	"time"
+)
+
+const (
+	restartBaseDelay = time.Second
+	restartMaxDelay  = time.Minute
+	// stableRunTime resets the restart delay of a worker which worked this
+	// long before panicking again.
+	stableRunTime = 5 * time.Minute
+)
+
+// Panics counts recovered panics by source: "http" or the worker name.
+var Panics = expvar.NewMap("panics")
+
+// Restarts counts restarts of supervised workers by worker name.
+var Restarts = expvar.NewMap("worker_restarts")
+
+// Report describes a recovered panic.
+type Report struct {
+	Time      time.Time `json:"time"`
+	Source    string    `json:"source"`
+	RequestID string    `json:"request_id,omitempty"`
+	Method    string    `json:"method,omitempty"`
+	Path      string    `json:"path,omitempty"`
+	Panic     string    `json:"panic"`
+	Stack     string    `json:"stack"`
+}
+
+// Sink stores crash reports.
+type Sink interface {
+	Write(report Report) error
+}
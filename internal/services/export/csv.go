@@ -0,0 +1,76 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvWriter writes the statement straight to the output. encoding/csv
+// buffers only a few kilobytes, so memory use does not depend on the
+// statement length.
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+
+	return &csvWriter{w: cw}
+}
+
+func (c *csvWriter) Begin(summary Summary) error {
+	records := [][]string{
+		{"Statement from", periodBound(summary.From), "to", periodBound(summary.To)},
+		{"Generated at", summary.GeneratedAt},
+		{"Opening balance", formatAmount(summary.OpeningBalance)},
+		{},
+		{"Date", "Type", "Reference", "Amount", "Balance"},
+	}
+
+	for _, record := range records {
+		if err := c.w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *csvWriter) Entry(entry Entry) error {
+	return c.w.Write([]string{
+		entry.OccurredAt,
+		entry.Type,
+		csvText(entry.Reference),
+		formatAmount(entry.Amount),
+		formatAmount(entry.Balance),
+	})
+}
+
+func (c *csvWriter) End(closingBalance float64) error {
+	if err := c.w.Write([]string{}); err != nil {
+		return err
+	}
+
+	if err := c.w.Write([]string{"Closing balance", formatAmount(closingBalance)}); err != nil {
+		return err
+	}
+
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// csvText stops spreadsheets from evaluating a text cell as a formula.
+func csvText(value string) string {
+	if value != "" && strings.ContainsAny(value[:1], "=+-@\t\r") {
+		return "'" + value
+	}
+
+	return value
+}
+
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', amountPrecision, 64)
+}
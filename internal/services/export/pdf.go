@@ -0,0 +1,120 @@
+package export
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+)
+
+const (
+	pdfFont       = "Helvetica"
+	pdfMargin     = 15.0
+	pdfRowHeight  = 6.0
+	pdfTitleSize  = 14.0
+	pdfBodySize   = 9.0
+	pdfHeaderFill = 230
+)
+
+// pdfColumns are the table columns and their widths in millimetres.
+var pdfColumns = []struct {
+	title string
+	width float64
+	align string
+}{
+	{"Date", 45, "L"},
+	{"Type", 25, "L"},
+	{"Reference", 50, "L"},
+	{"Amount", 30, "R"},
+	{"Balance", 30, "R"},
+}
+
+// pdfWriter renders the statement with fpdf. fpdf keeps the uncompressed
+// content of every page in memory until End writes the document, so the
+// memory use grows with the statement; callers refuse statements longer than
+// PDFMaxEntries up front.
+type pdfWriter struct {
+	out io.Writer
+	pdf *fpdf.Fpdf
+	tr  func(string) string
+}
+
+func newPDFWriter(w io.Writer) *pdfWriter {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.SetCompression(true)
+	pdf.AliasNbPages("")
+
+	p := &pdfWriter{out: w, pdf: pdf, tr: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin + 5)
+		pdf.SetFont(pdfFont, "", pdfBodySize-1)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	return p
+}
+
+func (p *pdfWriter) Begin(summary Summary) error {
+	p.pdf.AddPage()
+
+	p.pdf.SetFont(pdfFont, "B", pdfTitleSize)
+	p.pdf.CellFormat(0, 10, "Account statement", "", 1, "L", false, 0, "")
+
+	p.pdf.SetFont(pdfFont, "", pdfBodySize)
+	p.line(fmt.Sprintf("Period: %s - %s", periodBound(summary.From), periodBound(summary.To)))
+	p.line("Generated at: " + summary.GeneratedAt)
+	p.line("Opening balance: " + formatAmount(summary.OpeningBalance))
+	p.pdf.Ln(pdfRowHeight)
+
+	p.tableHeader()
+
+	return p.pdf.Error()
+}
+
+func (p *pdfWriter) Entry(entry Entry) error {
+	_, pageHeight := p.pdf.GetPageSize()
+	if p.pdf.GetY()+pdfRowHeight > pageHeight-pdfMargin {
+		p.pdf.AddPage()
+		p.tableHeader()
+	}
+
+	values := []string{entry.OccurredAt, entry.Type, entry.Reference, formatAmount(entry.Amount), formatAmount(entry.Balance)}
+	for i, column := range pdfColumns {
+		p.pdf.CellFormat(column.width, pdfRowHeight, p.tr(values[i]), "B", 0, column.align, false, 0, "")
+	}
+	p.pdf.Ln(-1)
+
+	return p.pdf.Error()
+}
+
+func (p *pdfWriter) End(closingBalance float64) error {
+	p.pdf.Ln(pdfRowHeight)
+	p.pdf.SetFont(pdfFont, "B", pdfBodySize)
+	p.line("Closing balance: " + formatAmount(closingBalance))
+
+	return p.pdf.Output(p.out)
+}
+
+func (p *pdfWriter) tableHeader() {
+	p.pdf.SetFont(pdfFont, "B", pdfBodySize)
+	p.pdf.SetFillColor(pdfHeaderFill, pdfHeaderFill, pdfHeaderFill)
+	for _, column := range pdfColumns {
+		p.pdf.CellFormat(column.width, pdfRowHeight, column.title, "1", 0, column.align, true, 0, "")
+	}
+	p.pdf.Ln(-1)
+	p.pdf.SetFont(pdfFont, "", pdfBodySize)
+}
+
+func (p *pdfWriter) line(text string) {
+	p.pdf.CellFormat(0, pdfRowHeight, p.tr(text), "", 1, "L", false, 0, "")
+}
+
+func periodBound(value string) string {
+	if value == "" {
+		return "open"
+	}
+
+	return value
+}
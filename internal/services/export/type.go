@@ -0,0 +1,80 @@
+package export
+
+import (
+	"errors"
+	"io"
+)
+
+const (
+	FormatCSV = "csv"
+	FormatPDF = "pdf"
+
+	// amountPrecision is the number of decimals printed for amounts.
+	amountPrecision = 2
+
+	// PDFMaxEntries bounds the PDF statement, which is built in memory. It
+	// makes some 120 pages and a few megabytes of page content.
+	PDFMaxEntries = 5000
+)
+
+var ErrorUnknownFormat = errors.New("unknown statement export format")
+
+// Summary is the heading of a statement. An empty From or To means the
+// period is open on that side.
+type Summary struct {
+	From           string
+	To             string
+	GeneratedAt    string
+	OpeningBalance float64
+}
+
+// Entry is a single statement line.
+type Entry struct {
+	OccurredAt string
+	Type       string
+	Reference  string
+	Amount     float64
+	Balance    float64
+}
+
+// Writer renders a statement entry by entry. Begin is called once before the
+// entries and End once after them.
+type Writer interface {
+	Begin(summary Summary) error
+	Entry(entry Entry) error
+	End(closingBalance float64) error
+}
+
+// NewWriter returns the statement writer of the format writing to w.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatPDF:
+		return newPDFWriter(w), nil
+	default:
+		return nil, ErrorUnknownFormat
+	}
+}
+
+// MaxEntries returns the largest number of entries the format can render, or
+// zero when the format is not limited.
+func MaxEntries(format string) int {
+	if format == FormatPDF {
+		return PDFMaxEntries
+	}
+
+	return 0
+}
+
+// ContentType returns the media type of the format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatPDF:
+		return "application/pdf"
+	default:
+		return "application/octet-stream"
+	}
+}
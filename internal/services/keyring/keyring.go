@@ -0,0 +1,279 @@
+package keyring
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var ring Keyring
+
+// Init loads the keys from the manifest file. Without a manifest the
+// JWT_SECRET value is used as a single HS256 key.
+func Init(env config.AppEnvironment) error {
+	var keys []*Key
+	var err error
+
+	if env.GetJWTKeysFile() != "" {
+		keys, err = loadManifest(env.GetJWTKeysFile())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "file": env.GetJWTKeysFile()}
+			logger.Error("fail load jwt keys manifest", args)
+			return err
+		}
+	} else {
+		if env.GetJWTSecret() == "" {
+			return errors.New("jwt secret is empty")
+		}
+
+		keys = []*Key{{
+			ID:        defaultKeyID,
+			Algorithm: AlgHS256,
+			Status:    KeyStatusActive,
+			method:    jwt.SigningMethodHS256,
+			signKey:   []byte(env.GetJWTSecret()),
+			verifyKey: []byte(env.GetJWTSecret()),
+		}}
+	}
+
+	return ring.set(keys)
+}
+
+// Sign signs the claims with the active key and sets its id in the kid header.
+func Sign(claims jwt.Claims) (string, error) {
+	return ring.sign(claims)
+}
+
+// Keyfunc resolves the verification key by the kid header of the token.
+// It is meant to be passed to jwt.ParseWithClaims.
+func Keyfunc(t *jwt.Token) (interface{}, error) {
+	return ring.keyfunc(t)
+}
+
+// PublicJWKS returns the public parts of the asymmetric keys which are not retired.
+func PublicJWKS() JWKSet {
+	return ring.jwks()
+}
+
+func (k *Keyring) set(keys []*Key) error {
+	byID := make(map[string]*Key, len(keys))
+	var active *Key
+
+	for _, key := range keys {
+		if _, ok := byID[key.ID]; ok {
+			return fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		byID[key.ID] = key
+
+		if key.Status == KeyStatusActive {
+			if active != nil {
+				return fmt.Errorf("more than one active key: %q and %q", active.ID, key.ID)
+			}
+			if key.signKey == nil {
+				return fmt.Errorf("active key %q has no private key", key.ID)
+			}
+			active = key
+		}
+	}
+
+	if active == nil {
+		return ErrorNoActiveKey
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.keys = byID
+	k.active = active
+
+	return nil
+}
+
+func (k *Keyring) sign(claims jwt.Claims) (string, error) {
+	k.mu.RLock()
+	active := k.active
+	k.mu.RUnlock()
+
+	if active == nil {
+		return "", ErrorNoActiveKey
+	}
+
+	token := jwt.NewWithClaims(active.method, claims)
+	token.Header["kid"] = active.ID
+
+	return token.SignedString(active.signKey)
+}
+
+func (k *Keyring) keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+	if kid == "" {
+		kid = defaultKeyID
+	}
+
+	k.mu.RLock()
+	key, ok := k.keys[kid]
+	k.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrorUnknownKey
+	}
+
+	if key.Status == KeyStatusRetired {
+		return nil, ErrorRetiredKey
+	}
+
+	if t.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+	}
+
+	return key.verifyKey, nil
+}
+
+func (k *Keyring) jwks() JWKSet {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	set := JWKSet{Keys: []JWK{}}
+	for _, key := range k.keys {
+		if key.Status == KeyStatusRetired {
+			continue
+		}
+
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     key.ID,
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				N:         base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     key.ID,
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+
+	return set
+}
+
+func loadManifest(path string) ([]*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest Manifest
+	if err = json.Unmarshal(data, &manifest); err != nil {
+		return nil, err
+	}
+
+	var keys []*Key
+	for _, mk := range manifest.Keys {
+		if mk.Status == KeyStatusRetired {
+			keys = append(keys, &Key{ID: mk.ID, Algorithm: mk.Algorithm, Status: mk.Status})
+			continue
+		}
+
+		file := mk.File
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(path), file)
+		}
+
+		key, err := loadKey(mk, file)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", mk.ID, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func loadKey(mk ManifestKey, file string) (*Key, error) {
+	if mk.ID == "" {
+		return nil, errors.New("key id is empty")
+	}
+
+	if mk.Status != KeyStatusActive && mk.Status != KeyStatusVerify {
+		return nil, fmt.Errorf("unknown key status %q", mk.Status)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{ID: mk.ID, Algorithm: mk.Algorithm, Status: mk.Status}
+	isPrivate := strings.Contains(string(data), "PRIVATE KEY")
+
+	switch mk.Algorithm {
+	case AlgHS256:
+		secret := []byte(strings.TrimSpace(string(data)))
+		if len(secret) == 0 {
+			return nil, errors.New("secret is empty")
+		}
+		key.method = jwt.SigningMethodHS256
+		key.signKey = secret
+		key.verifyKey = secret
+	case AlgRS256:
+		key.method = jwt.SigningMethodRS256
+		if isPrivate {
+			private, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+			key.signKey = private
+			key.verifyKey = &private.PublicKey
+		} else {
+			key.verifyKey, err = jwt.ParseRSAPublicKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case AlgEdDSA:
+		key.method = jwt.SigningMethodEdDSA
+		if isPrivate {
+			private, err := jwt.ParseEdPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+			edPrivate, ok := private.(ed25519.PrivateKey)
+			if !ok {
+				return nil, errors.New("key is not an Ed25519 private key")
+			}
+			key.signKey = edPrivate
+			key.verifyKey = edPrivate.Public()
+		} else {
+			key.verifyKey, err = jwt.ParseEdPublicKeyFromPEM(data)
+			if err != nil {
+				return nil, err
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q", mk.Algorithm)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,78 @@
+package keyring
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	KeyStatusActive  = "active"
+	KeyStatusVerify  = "verify"
+	KeyStatusRetired = "retired"
+
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+
+	// defaultKeyID is used for the JWT_SECRET key and for tokens issued
+	// before key ids were introduced.
+	defaultKeyID = "default"
+)
+
+var ErrorUnknownKey = errors.New("unknown signing key")
+var ErrorRetiredKey = errors.New("signing key is retired")
+var ErrorNoActiveKey = errors.New("no active signing key")
+
+// Manifest describes the keys file referenced by JWT_KEYS_FILE. Relative key
+// paths are resolved against the manifest directory.
+//
+//	{"keys": [
+//	  {"kid": "2024-10", "alg": "RS256", "file": "rs256.pem", "status": "active"},
+//	  {"kid": "2024-04", "alg": "HS256", "file": "hs256.secret", "status": "verify"}
+//	]}
+type Manifest struct {
+	Keys []ManifestKey `json:"keys"`
+}
+
+type ManifestKey struct {
+	ID        string `json:"kid"`
+	Algorithm string `json:"alg"`
+	File      string `json:"file"`
+	Status    string `json:"status"`
+}
+
+// Key is a loaded signing key. Verify-only asymmetric keys may be loaded from
+// a public key file, so signKey can be nil.
+type Key struct {
+	ID        string
+	Algorithm string
+	Status    string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+type Keyring struct {
+	mu     sync.RWMutex
+	keys   map[string]*Key
+	active *Key
+}
+
+// JWK is a public key in the JSON Web Key format (RFC 7517).
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
@@ -4,4 +4,5 @@ type AppLogger interface {
 	Info(msg string, args map[string]interface{})
 	Error(msg string, args map[string]interface{})
 	Fatal(msg string, args map[string]interface{})
+	Security(event string, args map[string]interface{})
 }
@@ -49,6 +49,10 @@ func Fatal(msg string, args map[string]interface{}) {
 	log.Fatal(msg, args)
 }
 
+func Security(event string, args map[string]interface{}) {
+	log.Security(event, args)
+}
+
 func Sync() {
 	log.log.Sync()
 }
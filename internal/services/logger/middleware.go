@@ -24,11 +24,12 @@ func Middleware(h http.HandlerFunc) http.HandlerFunc {
 		duration := time.Since(start)
 
 		args := map[string]interface{}{
-			"uri":      r.RequestURI,
-			"method":   r.Method,
-			"status":   responseData.Status,
-			"duration": duration,
-			"size":     responseData.Size,
+			"request_id": RequestID(r.Context()),
+			"uri":        r.RequestURI,
+			"method":     r.Method,
+			"status":     responseData.Status,
+			"duration":   duration,
+			"size":       responseData.Size,
 		}
 		log.Info("request info", args)
 	}
@@ -22,6 +22,13 @@ func (l *Logger) Fatal(msg string, args map[string]interface{}) {
 	l.log.Fatal(msg, l.prepareArgs(args)...)
 }
 
+// Security logs an audit event (lockouts, suspicious activity) so it can be
+// filtered out of the regular application log by the "security_event" field.
+func (l *Logger) Security(event string, args map[string]interface{}) {
+	fields := append(l.prepareArgs(args), zap.String("security_event", event))
+	l.log.Warn("security event", fields...)
+}
+
 func (l *Logger) prepareArgs(args map[string]interface{}) []zap.Field {
 	var r []zap.Field
 	for k, v := range args {
@@ -55,3 +62,8 @@ func (r *loggingResponseWriter) WriteHeader(statusCode int) {
 	r.ResponseWriter.WriteHeader(statusCode)
 	r.responseData.Status = statusCode
 }
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *loggingResponseWriter) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
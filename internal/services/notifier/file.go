@@ -0,0 +1,58 @@
+package notifier
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+)
+
+// FileNotifier appends messages to a file. It is meant for local development.
+type FileNotifier struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileNotifier(path string) *FileNotifier {
+	return &FileNotifier{path: path}
+}
+
+func (n *FileNotifier) Send(msg Message) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	f, err := os.OpenFile(n.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "--- %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC3339), msg.To,
+		msg.Subject, msg.Body)
+
+	return err
+}
+
+// LogNotifier records sent messages in the application log. The body is
+// never logged, as it carries secrets such as reset tokens; use the file
+// notifier to read messages during development.
+type LogNotifier struct{}
+
+func (n *LogNotifier) Send(msg Message) error {
+	args := map[string]interface{}{"to": msg.To, "subject": msg.Subject}
+	logger.Info("notification", args)
+
+	return nil
+}
+
+// NoneNotifier drops messages. It is used when no notifier is configured.
+type NoneNotifier struct{}
+
+func (n *NoneNotifier) Send(msg Message) error {
+	args := map[string]interface{}{"to": msg.To, "subject": msg.Subject}
+	logger.Error("notification dropped: no notifier configured", args)
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+)
+
+var current Notifier = &NoneNotifier{}
+
+// Init selects the notifier configured by NOTIFIER.
+func Init(env config.AppEnvironment) error {
+	switch env.GetNotifier() {
+	case config.NotifierSMTP:
+		if env.GetSMTPHost() == "" || env.GetSMTPFrom() == "" {
+			return errors.New("smtp notifier requires SMTP_HOST and SMTP_FROM")
+		}
+
+		current = NewSMTPNotifier(env.GetSMTPHost(), env.GetSMTPPort(), env.GetSMTPUsername(), env.GetSMTPPassword(),
+			env.GetSMTPFrom())
+	case config.NotifierLog:
+		if env.GetAppMode() != config.AppModeDevelopment {
+			return errors.New("log notifier is only allowed in development mode")
+		}
+
+		current = &LogNotifier{}
+	case config.NotifierFile:
+		if env.GetNotifierFile() == "" {
+			return errors.New("file notifier requires NOTIFIER_FILE")
+		}
+
+		current = NewFileNotifier(env.GetNotifierFile())
+	case config.NotifierNone:
+		current = &NoneNotifier{}
+	default:
+		return fmt.Errorf("unknown notifier %q", env.GetNotifier())
+	}
+
+	args := map[string]interface{}{"notifier": env.GetNotifier()}
+	logger.Info("notifier initialized", args)
+
+	return nil
+}
+
+func Send(msg Message) error {
+	if msg.To == "" {
+		return ErrorNoRecipient
+	}
+
+	return current.Send(msg)
+}
@@ -0,0 +1,16 @@
+package notifier
+
+import "errors"
+
+var ErrorNoRecipient = errors.New("notification recipient is empty")
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers messages to users, e.g. password reset tokens.
+type Notifier interface {
+	Send(msg Message) error
+}
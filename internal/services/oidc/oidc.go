@@ -0,0 +1,345 @@
+package oidc
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/keyring"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var provider *Provider
+
+// Init configures the identity provider. The login flow stays disabled when
+// no issuer is set.
+func Init(env config.AppEnvironment) error {
+	if env.GetOIDCIssuer() == "" {
+		provider = nil
+		return nil
+	}
+
+	if env.GetOIDCClientID() == "" || env.GetOIDCRedirectURL() == "" {
+		return errors.New("oidc client id and redirect url are required when the issuer is set")
+	}
+
+	provider = &Provider{
+		issuer:       strings.TrimSuffix(env.GetOIDCIssuer(), "/"),
+		clientID:     env.GetOIDCClientID(),
+		clientSecret: env.GetOIDCClientSecret(),
+		redirectURL:  env.GetOIDCRedirectURL(),
+		roleClaim:    env.GetOIDCRoleClaim(),
+		client:       &http.Client{Timeout: requestTimeout},
+	}
+
+	return nil
+}
+
+func Enabled() bool {
+	return provider != nil
+}
+
+// AuthCodeURL returns the provider login page URL of the authorization code
+// flow with a PKCE S256 challenge derived from the verifier.
+func AuthCodeURL(state string, nonce string, verifier string) (string, error) {
+	if provider == nil {
+		return "", ErrorDisabled
+	}
+
+	discovery, err := provider.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+
+	challenge := sha256.Sum256([]byte(verifier))
+
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", provider.clientID)
+	v.Set("redirect_uri", provider.redirectURL)
+	v.Set("scope", "openid profile email")
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+	v.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	v.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(discovery.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	return discovery.AuthorizationEndpoint + separator + v.Encode(), nil
+}
+
+// Exchange redeems the authorization code and verifies the returned ID token.
+func Exchange(code string, verifier string, nonce string) (Identity, error) {
+	if provider == nil {
+		return Identity{}, ErrorDisabled
+	}
+
+	discovery, err := provider.getDiscovery()
+	if err != nil {
+		return Identity{}, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", provider.redirectURL)
+	form.Set("client_id", provider.clientID)
+	form.Set("code_verifier", verifier)
+
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Identity{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if provider.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(provider.clientID), url.QueryEscape(provider.clientSecret))
+	}
+
+	resp, err := provider.client.Do(req)
+	if err != nil {
+		return Identity{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		args := map[string]interface{}{"status": resp.StatusCode, "body": string(body)}
+		logger.Error("oidc token endpoint returned an error", args)
+		return Identity{}, ErrorTokenExchange
+	}
+
+	var tokens tokenResponse
+	if err = json.Unmarshal(body, &tokens); err != nil {
+		return Identity{}, err
+	}
+
+	if tokens.IDToken == "" {
+		return Identity{}, fmt.Errorf("%w: no id token in response", ErrorTokenExchange)
+	}
+
+	return provider.verify(tokens.IDToken, nonce)
+}
+
+// verify checks the signature, issuer, audience, lifetime and nonce of the
+// ID token and extracts the identity.
+func (p *Provider) verify(idToken string, nonce string) (Identity, error) {
+	claims := &idTokenClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}))
+	token, err := parser.ParseWithClaims(idToken, claims, p.keyfunc)
+	if err != nil {
+		var validationErr *jwt.ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Errors&^(jwt.ValidationErrorExpired|jwt.ValidationErrorIssuedAt|jwt.ValidationErrorNotValidYet) != 0 {
+			return Identity{}, fmt.Errorf("%w: %v", ErrorInvalidIDToken, err)
+		}
+	}
+
+	if token == nil {
+		return Identity{}, ErrorInvalidIDToken
+	}
+
+	now := time.Now()
+	if claims.ExpiresAt == nil || now.After(claims.ExpiresAt.Add(clockSkew)) {
+		return Identity{}, fmt.Errorf("%w: token expired", ErrorInvalidIDToken)
+	}
+
+	if claims.IssuedAt != nil && claims.IssuedAt.After(now.Add(clockSkew)) {
+		return Identity{}, fmt.Errorf("%w: token issued in the future", ErrorInvalidIDToken)
+	}
+
+	if strings.TrimSuffix(claims.Issuer, "/") != p.issuer {
+		return Identity{}, fmt.Errorf("%w: unexpected issuer %s", ErrorInvalidIDToken, claims.Issuer)
+	}
+
+	if !claims.VerifyAudience(p.clientID, true) {
+		return Identity{}, fmt.Errorf("%w: unexpected audience", ErrorInvalidIDToken)
+	}
+
+	if claims.Nonce == "" || claims.Nonce != nonce {
+		return Identity{}, fmt.Errorf("%w: nonce mismatch", ErrorInvalidIDToken)
+	}
+
+	if claims.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: subject is empty", ErrorInvalidIDToken)
+	}
+
+	return Identity{
+		Issuer:            p.issuer,
+		Subject:           claims.Subject,
+		Email:             claims.Email,
+		EmailVerified:     isTrue(claims.EmailVerified),
+		PreferredUsername: claims.PreferredUsername,
+		Groups:            p.groups(token),
+	}, nil
+}
+
+// groups reads the configured role claim, which may be a string or a list.
+func (p *Provider) groups(token *jwt.Token) []string {
+	raw, _, err := jwt.NewParser().ParseUnverified(token.Raw, jwt.MapClaims{})
+	if err != nil {
+		return nil
+	}
+
+	claims, _ := raw.Claims.(jwt.MapClaims)
+	switch v := claims[p.roleClaim].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var groups []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+		return groups
+	default:
+		return nil
+	}
+}
+
+func (p *Provider) keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	if time.Since(p.keysLoaded) < jwksMinInterval {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	if err := p.loadKeys(); err != nil {
+		return nil, err
+	}
+
+	if key, ok := p.lookupKey(kid); ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// lookupKey finds the key by id. Tokens without a kid are accepted only when
+// the provider publishes a single key.
+func (p *Provider) lookupKey(kid string) (interface{}, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+// loadKeys must be called with p.mu held.
+func (p *Provider) loadKeys() error {
+	discovery, err := p.fetchDiscovery()
+	if err != nil {
+		return err
+	}
+
+	var set keyring.JWKSet
+	if err = p.getJSON(discovery.JWKSURI, &set); err != nil {
+		return err
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+
+		key, err := jwk.PublicKey()
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "kid": jwk.KeyID}
+			logger.Error("skip unsupported oidc provider key", args)
+			continue
+		}
+		keys[jwk.KeyID] = key
+	}
+
+	p.keys = keys
+	p.keysLoaded = time.Now()
+
+	return nil
+}
+
+func (p *Provider) getDiscovery() (*Discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.fetchDiscovery()
+}
+
+// fetchDiscovery must be called with p.mu held.
+func (p *Provider) fetchDiscovery() (*Discovery, error) {
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	var discovery Discovery
+	if err := p.getJSON(p.issuer+discoveryPath, &discovery); err != nil {
+		args := map[string]interface{}{"error": err.Error(), "issuer": p.issuer}
+		logger.Error("failed load oidc provider metadata", args)
+		return nil, err
+	}
+
+	if strings.TrimSuffix(discovery.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("oidc discovery issuer %s does not match %s", discovery.Issuer, p.issuer)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, errors.New("oidc discovery document is incomplete")
+	}
+
+	p.discovery = &discovery
+
+	return p.discovery, nil
+}
+
+func (p *Provider) getJSON(u string, v interface{}) error {
+	resp, err := p.client.Get(u)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, u)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// isTrue accepts both boolean and string email_verified values, as some
+// providers send the latter.
+func isTrue(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	default:
+		return false
+	}
+}
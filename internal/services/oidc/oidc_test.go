@@ -0,0 +1,228 @@
+package oidc
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/oidc/oidctest"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	testClientID = "gophermart"
+	testState    = "state"
+	testNonce    = "nonce"
+	testVerifier = "verifier-verifier-verifier-verifier-verifier"
+)
+
+func setupProvider(t *testing.T) *oidctest.Provider {
+	t.Helper()
+
+	idp, err := oidctest.NewProvider(testClientID)
+	if err != nil {
+		t.Fatalf("start identity provider: %v", err)
+	}
+	t.Cleanup(idp.Close)
+
+	provider = &Provider{
+		issuer:      idp.Issuer(),
+		clientID:    testClientID,
+		redirectURL: "http://localhost/callback",
+		roleClaim:   "groups",
+		client:      &http.Client{Timeout: requestTimeout},
+	}
+	t.Cleanup(func() { provider = nil })
+
+	return idp
+}
+
+// login runs the authorization code flow with the claims the provider puts
+// into the ID token and the verifier the client sends to the token endpoint.
+func login(t *testing.T, idp *oidctest.Provider, claims jwt.MapClaims, verifier string) (Identity, error) {
+	t.Helper()
+
+	authURL, err := AuthCodeURL(testState, testNonce, testVerifier)
+	if err != nil {
+		t.Fatalf("build auth code url: %v", err)
+	}
+
+	code, state, err := idp.Authorize(authURL, claims)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	if state != testState {
+		t.Fatalf("state = %q, want %q", state, testState)
+	}
+
+	return Exchange(code, verifier, testNonce)
+}
+
+func TestExchange(t *testing.T) {
+	idp := setupProvider(t)
+
+	identity, err := login(t, idp, jwt.MapClaims{
+		"sub":                "subject-1",
+		"email":              "user@example.com",
+		"email_verified":     "true",
+		"preferred_username": "user",
+		"groups":             []string{"support-team", "staff"},
+	}, testVerifier)
+	if err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	want := Identity{
+		Issuer:            idp.Issuer(),
+		Subject:           "subject-1",
+		Email:             "user@example.com",
+		EmailVerified:     true,
+		PreferredUsername: "user",
+		Groups:            []string{"support-team", "staff"},
+	}
+	if !reflect.DeepEqual(identity, want) {
+		t.Errorf("identity = %+v, want %+v", identity, want)
+	}
+}
+
+func TestExchangeInvalidIDToken(t *testing.T) {
+	idp := setupProvider(t)
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+	}{
+		{name: "nonce mismatch", claims: jwt.MapClaims{"nonce": "other"}},
+		{name: "missing nonce", claims: jwt.MapClaims{"nonce": nil}},
+		{name: "wrong audience", claims: jwt.MapClaims{"aud": "other-client"}},
+		{name: "wrong issuer", claims: jwt.MapClaims{"iss": "https://evil.example.com"}},
+		{name: "expired", claims: jwt.MapClaims{"exp": time.Now().Add(-clockSkew - time.Minute).Unix()}},
+		{name: "missing expiry", claims: jwt.MapClaims{"exp": nil}},
+		{name: "issued in the future", claims: jwt.MapClaims{"iat": time.Now().Add(clockSkew + time.Minute).Unix()}},
+		{name: "missing subject", claims: jwt.MapClaims{"sub": nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := jwt.MapClaims{"sub": "subject-1"}
+			for k, v := range tt.claims {
+				claims[k] = v
+			}
+
+			_, err := login(t, idp, claims, testVerifier)
+			if !errors.Is(err, ErrorInvalidIDToken) {
+				t.Errorf("err = %v, want %v", err, ErrorInvalidIDToken)
+			}
+		})
+	}
+}
+
+func TestExchangeAcceptsClockSkew(t *testing.T) {
+	idp := setupProvider(t)
+
+	claims := jwt.MapClaims{"sub": "subject-1", "exp": time.Now().Add(-clockSkew / 2).Unix()}
+	if _, err := login(t, idp, claims, testVerifier); err != nil {
+		t.Errorf("exchange: %v", err)
+	}
+}
+
+func TestExchangeWrongVerifier(t *testing.T) {
+	idp := setupProvider(t)
+
+	_, err := login(t, idp, jwt.MapClaims{"sub": "subject-1"}, "other-verifier")
+	if !errors.Is(err, ErrorTokenExchange) {
+		t.Errorf("err = %v, want %v", err, ErrorTokenExchange)
+	}
+
+	if idp.Redeemed() != 0 {
+		t.Errorf("redeemed = %d, want 0", idp.Redeemed())
+	}
+}
+
+func TestExchangeCodeReuse(t *testing.T) {
+	idp := setupProvider(t)
+
+	authURL, err := AuthCodeURL(testState, testNonce, testVerifier)
+	if err != nil {
+		t.Fatalf("build auth code url: %v", err)
+	}
+
+	code, _, err := idp.Authorize(authURL, jwt.MapClaims{"sub": "subject-1"})
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	if _, err = Exchange(code, testVerifier, testNonce); err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	if _, err = Exchange(code, testVerifier, testNonce); !errors.Is(err, ErrorTokenExchange) {
+		t.Errorf("err = %v, want %v", err, ErrorTokenExchange)
+	}
+}
+
+func TestExchangeKeyRotation(t *testing.T) {
+	idp := setupProvider(t)
+
+	claims := jwt.MapClaims{"sub": "subject-1"}
+	if _, err := login(t, idp, claims, testVerifier); err != nil {
+		t.Fatalf("exchange: %v", err)
+	}
+
+	if _, err := idp.RotateKey(false); err != nil {
+		t.Fatalf("rotate key: %v", err)
+	}
+
+	// Keys are not reloaded more often than jwksMinInterval, so a token with
+	// an unknown kid cannot be used to hammer the provider.
+	if _, err := login(t, idp, claims, testVerifier); !errors.Is(err, ErrorInvalidIDToken) {
+		t.Fatalf("err = %v, want %v", err, ErrorInvalidIDToken)
+	}
+
+	provider.mu.Lock()
+	provider.keysLoaded = time.Now().Add(-jwksMinInterval)
+	provider.mu.Unlock()
+
+	if _, err := login(t, idp, claims, testVerifier); err != nil {
+		t.Errorf("exchange after rotation: %v", err)
+	}
+}
+
+func TestExchangeGroups(t *testing.T) {
+	idp := setupProvider(t)
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []string
+	}{
+		{name: "list", value: []interface{}{"admins", 1, "staff"}, want: []string{"admins", "staff"}},
+		{name: "string", value: "admins", want: []string{"admins"}},
+		{name: "missing", value: nil, want: nil},
+		{name: "unsupported", value: map[string]string{"group": "admins"}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := login(t, idp, jwt.MapClaims{"sub": "subject-1", "groups": tt.value}, testVerifier)
+			if err != nil {
+				t.Fatalf("exchange: %v", err)
+			}
+
+			if !reflect.DeepEqual(identity.Groups, tt.want) {
+				t.Errorf("groups = %v, want %v", identity.Groups, tt.want)
+			}
+		})
+	}
+}
+
+func TestExchangeDisabled(t *testing.T) {
+	provider = nil
+
+	if _, err := Exchange("code", testVerifier, testNonce); !errors.Is(err, ErrorDisabled) {
+		t.Errorf("err = %v, want %v", err, ErrorDisabled)
+	}
+}
@@ -0,0 +1,235 @@
+// Package oidctest runs an OpenID Connect identity provider in-process for
+// tests of the login flow.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/keyring"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	DiscoveryPath = "/.well-known/openid-configuration"
+	AuthorizePath = "/authorize"
+	TokenPath     = "/token"
+	JWKSPath      = "/jwks"
+)
+
+var ErrorInvalidRequest = errors.New("invalid authorization request")
+
+// Provider serves discovery, JWKS and token endpoints. Authorization requests
+// are approved by Authorize instead of a login page, with the claims the test
+// wants the ID token to carry.
+type Provider struct {
+	Server   *httptest.Server
+	ClientID string
+
+	mu        sync.Mutex
+	keys      []*signingKey
+	active    *signingKey
+	grants    map[string]grant
+	redeemed  int
+	keyNumber int
+}
+
+type signingKey struct {
+	id  string
+	key *rsa.PrivateKey
+}
+
+type grant struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+// NewProvider starts the provider with a single signing key.
+func NewProvider(clientID string) (*Provider, error) {
+	p := &Provider{ClientID: clientID, grants: make(map[string]grant)}
+	if _, err := p.RotateKey(false); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(DiscoveryPath, p.discovery)
+	mux.HandleFunc(JWKSPath, p.jwks)
+	mux.HandleFunc(TokenPath, p.token)
+	p.Server = httptest.NewServer(mux)
+
+	return p, nil
+}
+
+func (p *Provider) Close() {
+	p.Server.Close()
+}
+
+func (p *Provider) Issuer() string {
+	return p.Server.URL
+}
+
+// RotateKey makes a new key active and returns its id. The previous keys stay
+// published when keep is set.
+func (p *Provider) RotateKey(keep bool) (string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.keyNumber++
+	k := &signingKey{id: fmt.Sprintf("key-%d", p.keyNumber), key: key}
+	if !keep {
+		p.keys = nil
+	}
+	p.keys = append(p.keys, k)
+	p.active = k
+
+	return k.id, nil
+}
+
+// Redeemed returns the number of authorization codes exchanged successfully.
+func (p *Provider) Redeemed() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.redeemed
+}
+
+// Authorize checks the authorization request built by the client and returns
+// the code and state the provider would redirect back with. The ID token gets
+// the issuer, audience, nonce and lifetime of a valid token; claims override
+// or extend them, and a nil claim value removes the claim.
+func (p *Provider) Authorize(authURL string, claims jwt.MapClaims) (string, string, error) {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	q := u.Query()
+	if u.Path != AuthorizePath || q.Get("response_type") != "code" || q.Get("client_id") != p.ClientID ||
+		q.Get("code_challenge_method") != "S256" || q.Get("code_challenge") == "" || q.Get("state") == "" {
+		return "", "", ErrorInvalidRequest
+	}
+
+	now := time.Now()
+	idClaims := jwt.MapClaims{
+		"iss":   p.Issuer(),
+		"aud":   p.ClientID,
+		"nonce": q.Get("nonce"),
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+	}
+	for k, v := range claims {
+		if v == nil {
+			delete(idClaims, k)
+			continue
+		}
+		idClaims[k] = v
+	}
+
+	code, err := randomString()
+	if err != nil {
+		return "", "", err
+	}
+
+	p.mu.Lock()
+	p.grants[code] = grant{challenge: q.Get("code_challenge"), claims: idClaims}
+	p.mu.Unlock()
+
+	return code, q.Get("state"), nil
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{
+		"issuer":                 p.Issuer(),
+		"authorization_endpoint": p.Issuer() + AuthorizePath,
+		"token_endpoint":         p.Issuer() + TokenPath,
+		"jwks_uri":               p.Issuer() + JWKSPath,
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	set := keyring.JWKSet{}
+	for _, k := range p.keys {
+		set.Keys = append(set.Keys, keyring.JWK{
+			KeyType:   "RSA",
+			KeyID:     k.id,
+			Use:       "sig",
+			Algorithm: "RS256",
+			N:         base64.RawURLEncoding.EncodeToString(k.key.N.Bytes()),
+			E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.key.E)).Bytes()),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, set)
+}
+
+// token redeems a code once, and only with the verifier matching the PKCE
+// challenge of the authorization request.
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil || r.PostForm.Get("grant_type") != "authorization_code" ||
+		r.PostForm.Get("client_id") != p.ClientID {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	g, ok := p.grants[r.PostForm.Get("code")]
+	delete(p.grants, r.PostForm.Get("code"))
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, g.claims)
+	token.Header["kid"] = p.active.id
+	idToken, err := token.SignedString(p.active.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	p.redeemed++
+	writeJSON(w, http.StatusOK, map[string]string{
+		"id_token":     idToken,
+		"access_token": "access-token",
+		"token_type":   "Bearer",
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func randomString() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
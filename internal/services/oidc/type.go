@@ -0,0 +1,70 @@
+package oidc
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	discoveryPath   = "/.well-known/openid-configuration"
+	requestTimeout  = 10 * time.Second
+	jwksMinInterval = time.Minute
+	clockSkew       = time.Minute
+)
+
+var ErrorDisabled = errors.New("openid connect login is not configured")
+var ErrorInvalidIDToken = errors.New("invalid id token")
+var ErrorTokenExchange = errors.New("failed to exchange authorization code")
+
+// Discovery is the part of the provider metadata used by the login flow.
+type Discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider talks to one OpenID Connect identity provider. Metadata and keys
+// are fetched on first use and the keys are reloaded when a token is signed
+// with an unknown key.
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	roleClaim    string
+	client       *http.Client
+
+	mu         sync.Mutex
+	discovery  *Discovery
+	keys       map[string]interface{}
+	keysLoaded time.Time
+}
+
+// Identity is the verified user information of an ID token.
+type Identity struct {
+	Issuer            string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Groups            []string
+}
+
+type idTokenClaims struct {
+	jwt.RegisteredClaims
+	Nonce             string      `json:"nonce"`
+	Email             string      `json:"email"`
+	EmailVerified     interface{} `json:"email_verified"`
+	PreferredUsername string      `json:"preferred_username"`
+}
+
+type tokenResponse struct {
+	IDToken     string `json:"id_token"`
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+}
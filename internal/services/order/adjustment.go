@@ -0,0 +1,193 @@
+package order
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	ReasonGoodwill           = "goodwill"
+	ReasonFraudReversal      = "fraud_reversal"
+	ReasonAccrualCorrection  = "accrual_correction"
+	ReasonWithdrawCorrection = "withdraw_correction"
+	ReasonOther              = "other"
+
+	maxAdjustmentCommentLength = 500
+
+	// autoApprovalWindow is the period over which auto-approved adjustments
+	// of one admin are added up against the approval threshold, so a large
+	// adjustment cannot be split into small ones.
+	autoApprovalWindow = 24 * time.Hour
+)
+
+var reasonCodes = []string{ReasonGoodwill, ReasonFraudReversal, ReasonAccrualCorrection, ReasonWithdrawCorrection, ReasonOther}
+
+// CreateAdjustment credits or debits the user balance on behalf of an admin.
+// Adjustments apply immediately while the admin's auto-approved total of the
+// last day stays within the configured threshold; others stay pending until
+// a second admin approves them. Admins cannot adjust their own balance.
+func CreateAdjustment(actorID int, userID int, adjustmentType string, amount float64, reasonCode string, comment string) (Adjustment, error) {
+	cnf, err := config.GetAppConfig()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+		return Adjustment{}, err
+	}
+
+	if actorID == userID {
+		args := map[string]interface{}{"userID": userID}
+		logger.Security("self_adjustment_rejected", args)
+		return Adjustment{}, ErrorSelfAdjustment
+	}
+
+	comment = strings.TrimSpace(comment)
+	if err = validateAdjustment(adjustmentType, amount, reasonCode, comment); err != nil {
+		return Adjustment{}, err
+	}
+
+	adjustment := store.BalanceAdjustment{
+		UserID:     userID,
+		Type:       adjustmentType,
+		Amount:     amount,
+		ReasonCode: reasonCode,
+		Comment:    comment,
+		CreatedBy:  actorID,
+	}
+
+	since := time.Now().Add(-autoApprovalWindow)
+	created, err := store.CreateBalanceAdjustment(adjustment, cnf.GetAdjustmentApprovalThreshold(), since)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed create balance adjustment", args)
+		return Adjustment{}, err
+	}
+
+	args := map[string]interface{}{"adjustmentID": created.ID, "userID": userID, "actorID": actorID,
+		"type": adjustmentType, "amount": amount, "reason": reasonCode, "status": created.Status}
+	logger.Security("balance_adjustment_created", args)
+
+	return newAdjustment(created), nil
+}
+
+func ApproveAdjustment(id int, adminID int) (Adjustment, error) {
+	return decideAdjustment(id, adminID, store.AdjustmentStatusApproved)
+}
+
+func RejectAdjustment(id int, adminID int) (Adjustment, error) {
+	return decideAdjustment(id, adminID, store.AdjustmentStatusRejected)
+}
+
+// GetUserAdjustments returns all adjustments of the user including pending
+// and rejected ones.
+func GetUserAdjustments(userID int) ([]Adjustment, error) {
+	adjustments, err := store.GetBalanceAdjustmentsByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAdjustments(adjustments), nil
+}
+
+// GetAppliedAdjustments returns adjustments which changed the user balance.
+func GetAppliedAdjustments(userID int) ([]Adjustment, error) {
+	adjustments, err := GetUserAdjustments(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Adjustment
+	for _, a := range adjustments {
+		if a.Status == store.AdjustmentStatusApproved {
+			result = append(result, a)
+		}
+	}
+
+	return result, nil
+}
+
+func GetPendingAdjustments() ([]Adjustment, error) {
+	adjustments, err := store.GetBalanceAdjustmentsByStatus(store.AdjustmentStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	return newAdjustments(adjustments), nil
+}
+
+func decideAdjustment(id int, adminID int, status string) (Adjustment, error) {
+	decided, err := store.DecideBalanceAdjustment(id, status, adminID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Adjustment{}, ErrorAdjustmentNotPending
+		}
+
+		return Adjustment{}, err
+	}
+
+	args := map[string]interface{}{"adjustmentID": id, "userID": decided.UserID, "actorID": adminID, "status": status}
+	logger.Security("balance_adjustment_decided", args)
+
+	return newAdjustment(decided), nil
+}
+
+func validateAdjustment(adjustmentType string, amount float64, reasonCode string, comment string) error {
+	if adjustmentType != store.AdjustmentTypeCredit && adjustmentType != store.AdjustmentTypeDebit {
+		return ErrorInvalidAdjustment
+	}
+
+	if amount <= 0 || math.IsInf(amount, 0) || math.IsNaN(amount) {
+		return ErrorInvalidAdjustment
+	}
+
+	if comment == "" || len([]rune(comment)) > maxAdjustmentCommentLength {
+		return ErrorInvalidAdjustment
+	}
+
+	for _, code := range reasonCodes {
+		if code == reasonCode {
+			return nil
+		}
+	}
+
+	return ErrorUnknownReasonCode
+}
+
+func newAdjustments(adjustments []store.BalanceAdjustment) []Adjustment {
+	result := make([]Adjustment, 0, len(adjustments))
+	for _, a := range adjustments {
+		result = append(result, newAdjustment(a))
+	}
+
+	return result
+}
+
+func newAdjustment(a store.BalanceAdjustment) Adjustment {
+	adjustment := Adjustment{
+		ID:         a.ID,
+		UserID:     a.UserID,
+		Type:       a.Type,
+		Amount:     a.Amount,
+		ReasonCode: a.ReasonCode,
+		Comment:    a.Comment,
+		Status:     a.Status,
+		CreatedBy:  a.CreatedBy,
+		CreatedAt:  a.CreatedAt.Format(time.RFC3339),
+	}
+
+	if a.DecidedBy != nil {
+		adjustment.DecidedBy = *a.DecidedBy
+	}
+
+	if a.DecidedAt != nil {
+		adjustment.DecidedAt = a.DecidedAt.Format(time.RFC3339)
+	}
+
+	return adjustment
+}
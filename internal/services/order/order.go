@@ -78,7 +78,12 @@ func GetBalanceByUserID(userID int) (UserBalance, error) {
 		return UserBalance{}, err
 	}
 
-	return UserBalance{Current: accrualAmount - withdrawAmount, Withdrawn: withdrawAmount}, nil
+	adjustmentAmount, err := store.GetAdjustmentAmountByUserID(userID)
+	if err != nil {
+		return UserBalance{}, err
+	}
+
+	return UserBalance{Current: accrualAmount + adjustmentAmount - withdrawAmount, Withdrawn: withdrawAmount}, nil
 }
 
 func CreateWithdraw(number string, amount float64, userID int) error {
@@ -130,6 +135,40 @@ func GetWithdrawsByUserID(userID int) (WithdrawList, error) {
 	return list, nil
 }
 
+// RequeueOrder sends an order stuck in the accrual pipeline to the accrual
+// system again. Processed orders are final and cannot be requeued.
+func RequeueOrder(number string) error {
+	orderNum, err := strconv.Atoi(number)
+	if err != nil {
+		return ErrorIncorrectOrderNumber
+	}
+
+	o, err := store.GetOrderByNumber(orderNum)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrorOrderNotFound
+		}
+
+		return err
+	}
+
+	if o.Status == store.OrderStatusProcessed {
+		return ErrorOrderAlreadyProcessed
+	}
+
+	o.Status = store.OrderStatusNew
+	err = store.UpdateOrder(o)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "orderNum": number}
+		logger.Error("failed requeue order", args)
+		return err
+	}
+
+	go accrual.CheckAccrualAmount(orderNum)
+
+	return nil
+}
+
 func checkNumber(number int) bool {
 	return (number%10+checksum(number/10))%10 == 0
 }
@@ -0,0 +1,103 @@
+package order
+
+import (
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+)
+
+const (
+	maxStatementLimit = 100
+
+	// StatementExportTimeout is how long a streamed export may take.
+	StatementExportTimeout = store.StatementExportTimeout
+)
+
+// GetStatement returns a page of the user's accruals, withdrawals and applied
+// adjustments within the period [from, to), oldest first, with the balance
+// after each entry. A nil bound leaves that side of the period open.
+func GetStatement(userID int, from *time.Time, to *time.Time, limit int, offset int) ([]StatementEntry, error) {
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, ErrorInvalidStatementPeriod
+	}
+
+	if limit <= 0 || limit > maxStatementLimit {
+		limit = maxStatementLimit
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	filter := store.StatementFilter{From: from, To: to, Limit: limit, Offset: offset}
+	entries, err := store.GetStatement(userID, filter)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed get statement", args)
+		return nil, err
+	}
+
+	result := make([]StatementEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, newStatementEntry(e))
+	}
+
+	return result, nil
+}
+
+// EachStatementEntry streams every entry of the period [from, to) to fn in
+// chronological order.
+func EachStatementEntry(userID int, from *time.Time, to *time.Time, fn func(StatementEntry) error) error {
+	if from != nil && to != nil && !from.Before(*to) {
+		return ErrorInvalidStatementPeriod
+	}
+
+	filter := store.StatementFilter{From: from, To: to}
+	return store.EachStatementEntry(userID, filter, func(e store.StatementEntry) error {
+		return fn(newStatementEntry(e))
+	})
+}
+
+// CountStatementEntries returns the number of entries in the period [from, to).
+func CountStatementEntries(userID int, from *time.Time, to *time.Time) (int, error) {
+	if from != nil && to != nil && !from.Before(*to) {
+		return 0, ErrorInvalidStatementPeriod
+	}
+
+	count, err := store.CountStatementEntries(userID, store.StatementFilter{From: from, To: to})
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed count statement entries", args)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetOpeningBalance returns the user balance at the start of a period. A
+// period without a start opens with an empty balance.
+func GetOpeningBalance(userID int, from *time.Time) (float64, error) {
+	if from == nil {
+		return 0, nil
+	}
+
+	balance, err := store.GetOpeningBalance(userID, *from)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed get opening balance", args)
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func newStatementEntry(e store.StatementEntry) StatementEntry {
+	return StatementEntry{
+		Type:       e.Type,
+		Reference:  e.Reference,
+		Amount:     e.Amount,
+		Balance:    e.Balance,
+		OccurredAt: e.OccurredAt.Format(time.RFC3339),
+	}
+}
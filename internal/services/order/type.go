@@ -8,6 +8,13 @@ var ErrorOrderAlreadyAddedByUser = errors.New("order already added by user")
 var ErrorOrderAlreadyAddedByOtherUser = errors.New("order already added by other user")
 var ErrorIncorrectWithdrawNumber = errors.New("incorrect withdraw number")
 var ErrorIncorrectOrderNumber = errors.New("incorrect order number")
+var ErrorOrderNotFound = errors.New("order not found")
+var ErrorOrderAlreadyProcessed = errors.New("order already processed")
+var ErrorInvalidAdjustment = errors.New("invalid balance adjustment")
+var ErrorUnknownReasonCode = errors.New("unknown adjustment reason code")
+var ErrorAdjustmentNotPending = errors.New("adjustment not found or cannot be decided by this admin")
+var ErrorSelfAdjustment = errors.New("admins cannot adjust their own balance")
+var ErrorInvalidStatementPeriod = errors.New("statement period start must be before its end")
 
 type Order struct {
 	Number     int
@@ -30,3 +37,27 @@ type Withdraw struct {
 }
 
 type WithdrawList []Withdraw
+
+type Adjustment struct {
+	ID         int
+	UserID     int
+	Type       string
+	Amount     float64
+	ReasonCode string
+	Comment    string
+	Status     string
+	CreatedBy  int
+	DecidedBy  int
+	CreatedAt  string
+	DecidedAt  string
+}
+
+// StatementEntry is a single movement of the user balance. Amount is negative
+// for withdrawals and debits.
+type StatementEntry struct {
+	Type       string
+	Reference  string
+	Amount     float64
+	Balance    float64
+	OccurredAt string
+}
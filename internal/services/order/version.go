@@ -0,0 +1,19 @@
+package order
+
+import "github.com/MagicNetLab/go-diploma/internal/services/store"
+
+// OrdersVersion identifies the current state of the user's order list.
+func OrdersVersion(userID int) (string, error) {
+	return store.GetOrdersVersion(userID)
+}
+
+// WithdrawalsVersion identifies the current state of the user's withdrawals.
+func WithdrawalsVersion(userID int) (string, error) {
+	return store.GetWithdrawalsVersion(userID)
+}
+
+// BalanceVersion identifies the current state of everything the user's
+// balance is calculated from.
+func BalanceVersion(userID int) (string, error) {
+	return store.GetBalanceVersion(userID)
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/order"
+	"github.com/MagicNetLab/go-diploma/internal/services/user"
+	"github.com/go-chi/chi/v5"
+)
+
+// AdjustmentListHandler returns the manual balance adjustments applied to
+// the balance of the current user.
+func AdjustmentListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := auth.UserID(r.Context())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error getting auth user id from request context", args)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		adjustments, err := order.GetAppliedAdjustments(userID)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("error getting user balance adjustments", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		response := make(UserAdjustmentsResponse, 0, len(adjustments))
+		for _, a := range adjustments {
+			response = append(response, UserAdjustment{
+				Type:        a.Type,
+				Amount:      a.Amount,
+				ReasonCode:  a.ReasonCode,
+				ProcessedAt: a.DecidedAt,
+			})
+		}
+
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func AdminCreateAdjustmentHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actorID, err := auth.UserID(r.Context())
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		userID, ok := userIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		var adjustmentRequest AdminAdjustmentRequest
+		if err := json.NewDecoder(r.Body).Decode(&adjustmentRequest); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error decoding balance adjustment request body", args)
+			writeDecodeError(w, err)
+			return
+		}
+
+		if !adjustmentRequest.IsValid() {
+			logger.Error("failed create balance adjustment: one or more request params is empty", nil)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		if _, err := user.GetAccount(userID); err != nil {
+			writeAdminUserError(w, err, userID)
+			return
+		}
+
+		adjustment, err := order.CreateAdjustment(actorID, userID, adjustmentRequest.Type, adjustmentRequest.Amount,
+			adjustmentRequest.ReasonCode, adjustmentRequest.Comment)
+		if err != nil {
+			if errors.Is(err, order.ErrorInvalidAdjustment) || errors.Is(err, order.ErrorUnknownReasonCode) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			if errors.Is(err, order.ErrorSelfAdjustment) {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("fail create balance adjustment", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusCreated, newAdminAdjustment(adjustment))
+	}
+}
+
+func AdminUserAdjustmentsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := userIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		adjustments, err := order.GetUserAdjustments(userID)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("error getting user balance adjustments", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newAdminAdjustmentsResponse(adjustments))
+	}
+}
+
+func AdminPendingAdjustmentsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		adjustments, err := order.GetPendingAdjustments()
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error getting pending balance adjustments", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newAdminAdjustmentsResponse(adjustments))
+	}
+}
+
+func AdminApproveAdjustmentHandler() http.HandlerFunc {
+	return adminDecideAdjustmentHandler(order.ApproveAdjustment)
+}
+
+func AdminRejectAdjustmentHandler() http.HandlerFunc {
+	return adminDecideAdjustmentHandler(order.RejectAdjustment)
+}
+
+func adminDecideAdjustmentHandler(decide func(id int, adminID int) (order.Adjustment, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		adminID, err := auth.UserID(r.Context())
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || id <= 0 {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		adjustment, err := decide(id, adminID)
+		if err != nil {
+			if errors.Is(err, order.ErrorAdjustmentNotPending) {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+
+			args := map[string]interface{}{"error": err.Error(), "adjustmentID": id}
+			logger.Error("fail decide balance adjustment", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newAdminAdjustment(adjustment))
+	}
+}
+
+func newAdminAdjustmentsResponse(adjustments []order.Adjustment) AdminAdjustmentsResponse {
+	response := make(AdminAdjustmentsResponse, 0, len(adjustments))
+	for _, a := range adjustments {
+		response = append(response, newAdminAdjustment(a))
+	}
+
+	return response
+}
+
+func newAdminAdjustment(a order.Adjustment) AdminAdjustment {
+	return AdminAdjustment{
+		ID:         a.ID,
+		UserID:     a.UserID,
+		Type:       a.Type,
+		Amount:     a.Amount,
+		ReasonCode: a.ReasonCode,
+		Comment:    a.Comment,
+		Status:     a.Status,
+		CreatedBy:  a.CreatedBy,
+		DecidedBy:  a.DecidedBy,
+		CreatedAt:  a.CreatedAt,
+		DecidedAt:  a.DecidedAt,
+	}
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/order"
+	"github.com/MagicNetLab/go-diploma/internal/services/user"
+	"github.com/go-chi/chi/v5"
+)
+
+func AdminUnlockUserHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var unlockRequest AdminUnlockUserRequest
+		if err := json.NewDecoder(r.Body).Decode(&unlockRequest); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error decoding unlock user request body", args)
+			writeDecodeError(w, err)
+			return
+		}
+
+		if !unlockRequest.IsValid() {
+			logger.Error("failed unlock user: login is empty", nil)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		user.Unlock(unlockRequest.Login)
+
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write([]byte("OK"))
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("Failed to write unlock user response", args)
+		}
+	}
+}
+
+func AdminSearchUsersHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+
+		accounts, err := user.SearchAccounts(query.Get("q"), limit, offset)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail search users", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		response := make(AdminUsersResponse, 0, len(accounts))
+		for _, a := range accounts {
+			response = append(response, newAdminUser(a))
+		}
+
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func AdminUserHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := userIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		account, err := user.GetAccount(userID)
+		if err != nil {
+			writeAdminUserError(w, err, userID)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, newAdminUser(account))
+	}
+}
+
+func AdminUserOrdersHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := userIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		if _, err := user.GetAccount(userID); err != nil {
+			writeAdminUserError(w, err, userID)
+			return
+		}
+
+		userOrders, err := order.GetUserOrders(userID)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("error getting user orders", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		response := newUserOrdersResponse(userOrders)
+		if response == nil {
+			response = UserOrdersResponse{}
+		}
+
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func AdminUserWithdrawalsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := userIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		if _, err := user.GetAccount(userID); err != nil {
+			writeAdminUserError(w, err, userID)
+			return
+		}
+
+		result, err := order.GetWithdrawsByUserID(userID)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("error getting user withdrawals", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		response := newWithdrawResponse(result)
+		if response == nil {
+			response = WithdrawResponse{}
+		}
+
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+func AdminBlockUserHandler() http.HandlerFunc {
+	return adminUserActionHandler(user.Block)
+}
+
+func AdminUnblockUserHandler() http.HandlerFunc {
+	return adminUserActionHandler(user.Unblock)
+}
+
+func AdminDeleteUserHandler() http.HandlerFunc {
+	return adminUserActionHandler(user.Delete)
+}
+
+func AdminSetUserRoleHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actorID, err := auth.UserID(r.Context())
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		userID, ok := userIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		var roleRequest AdminSetRoleRequest
+		if err := json.NewDecoder(r.Body).Decode(&roleRequest); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error decoding set role request body", args)
+			writeDecodeError(w, err)
+			return
+		}
+
+		if !roleRequest.IsValid() {
+			logger.Error("failed set user role: role is empty", nil)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err = user.SetRole(actorID, userID, roleRequest.Role)
+		if err != nil {
+			if errors.Is(err, user.ErrorInvalidRole) {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			writeAdminUserError(w, err, userID)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}
+}
+
+func AdminRequeueOrderHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		number := chi.URLParam(r, "number")
+
+		err := order.RequeueOrder(number)
+		if err != nil {
+			switch {
+			case errors.Is(err, order.ErrorIncorrectOrderNumber):
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			case errors.Is(err, order.ErrorOrderNotFound):
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			case errors.Is(err, order.ErrorOrderAlreadyProcessed):
+				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+			default:
+				args := map[string]interface{}{"error": err.Error(), "order": number}
+				logger.Error("fail requeue order", args)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("Accepted"))
+	}
+}
+
+func adminUserActionHandler(action func(actorID int, userID int) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actorID, err := auth.UserID(r.Context())
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		userID, ok := userIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		if err = action(actorID, userID); err != nil {
+			writeAdminUserError(w, err, userID)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}
+}
+
+func userIDParam(w http.ResponseWriter, r *http.Request) (int, bool) {
+	userID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil || userID <= 0 {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return 0, false
+	}
+
+	return userID, true
+}
+
+func writeAdminUserError(w http.ResponseWriter, err error, userID int) {
+	if errors.Is(err, user.ErrorUserNotFound) {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	args := map[string]interface{}{"error": err.Error(), "userID": userID}
+	logger.Error("fail admin user request", args)
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+func writeJSON(w http.ResponseWriter, status int, response interface{}) {
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("Failed to write response", args)
+	}
+}
+
+// WriteError writes an ErrorResponse with the status code.
+func WriteError(w http.ResponseWriter, status int, code string, message string) {
+	writeJSON(w, status, ErrorResponse{Error: code, Message: message})
+}
+
+// WriteAccountStatusError answers requests of blocked and deleted accounts.
+// It returns false when err is not an account status error.
+func WriteAccountStatusError(w http.ResponseWriter, err error) bool {
+	var statusErr *user.AccountStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	WriteError(w, http.StatusForbidden, statusErr.Code(), statusErr.Error())
+	return true
+}
+
+func newAdminUser(a user.Account) AdminUser {
+	return AdminUser{
+		ID:            a.ID,
+		Login:         a.Login,
+		Email:         a.Email,
+		Role:          a.Role,
+		Status:        a.Status,
+		TOTPEnabled:   a.TOTPEnabled,
+		LoginConflict: a.LoginConflict,
+	}
+}
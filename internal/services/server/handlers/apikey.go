@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/user"
+	"github.com/go-chi/chi/v5"
+)
+
+func APIKeyListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := auth.UserID(r.Context())
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		writeAPIKeys(w, userID)
+	}
+}
+
+func CreateAPIKeyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := auth.UserID(r.Context())
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		createAPIKey(w, r, userID, userID)
+	}
+}
+
+func RevokeAPIKeyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := auth.UserID(r.Context())
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		revokeAPIKey(w, userID, userID, chi.URLParam(r, "id"))
+	}
+}
+
+func AdminAPIKeyListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, ok := userIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		writeAPIKeys(w, userID)
+	}
+}
+
+func AdminCreateAPIKeyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actorID, err := auth.UserID(r.Context())
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		userID, ok := userIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		if _, err := user.GetAccount(userID); err != nil {
+			writeAdminUserError(w, err, userID)
+			return
+		}
+
+		createAPIKey(w, r, actorID, userID)
+	}
+}
+
+func AdminRevokeAPIKeyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actorID, err := auth.UserID(r.Context())
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		userID, ok := userIDParam(w, r)
+		if !ok {
+			return
+		}
+
+		revokeAPIKey(w, actorID, userID, chi.URLParam(r, "keyID"))
+	}
+}
+
+func createAPIKey(w http.ResponseWriter, r *http.Request, actorID int, userID int) {
+	var keyRequest CreateAPIKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&keyRequest); err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("error decoding api key request body", args)
+		writeDecodeError(w, err)
+		return
+	}
+
+	if !keyRequest.IsValid() {
+		logger.Error("failed create api key: one or more request params is empty", nil)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	lifetime := time.Duration(keyRequest.ExpiresInDays) * 24 * time.Hour
+	apiKey, key, err := user.CreateAPIKey(actorID, userID, keyRequest.Name, keyRequest.Scopes, lifetime)
+	if err != nil {
+		var validationErrors user.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			writeValidationErrors(w, validationErrors)
+			return
+		}
+
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("fail create api key", args)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, http.StatusCreated, CreateAPIKeyResponse{APIKeyResponse: newAPIKeyResponse(apiKey), Key: key})
+}
+
+func writeAPIKeys(w http.ResponseWriter, userID int) {
+	keys, err := user.ListAPIKeys(userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("fail list api keys", args)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	response := make(APIKeysResponse, 0, len(keys))
+	for _, k := range keys {
+		response = append(response, newAPIKeyResponse(k))
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func revokeAPIKey(w http.ResponseWriter, actorID int, userID int, keyParam string) {
+	keyID, err := strconv.Atoi(keyParam)
+	if err != nil || keyID <= 0 {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	err = user.RevokeAPIKey(actorID, userID, keyID)
+	if err != nil {
+		if errors.Is(err, user.ErrorAPIKeyNotFound) {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("fail revoke api key", args)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newAPIKeyResponse(k user.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		CreatedAt:  k.CreatedAt,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+	}
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// privateRevalidate lets clients keep user data but makes them ask whether
+// it is still current on every request.
+const privateRevalidate = "private, no-cache"
+
+// etag builds a weak entity tag for a resource of the user. The version is
+// hashed so that row counts and timestamps are not exposed to the client.
+func etag(userID int, resource string, version string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d:%s:%s", userID, resource, version)))
+
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified sets the validator headers and answers 304 when the request
+// carries a matching If-None-Match. It reports whether the response is done.
+func notModified(w http.ResponseWriter, r *http.Request, tag string) bool {
+	w.Header().Set("ETag", tag)
+	w.Header().Set("Cache-Control", privateRevalidate)
+	w.Header().Add("Vary", "Authorization, Cookie")
+
+	if !etagMatch(r.Header.Get("If-None-Match"), tag) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatch uses the weak comparison required for If-None-Match.
+func etagMatch(header string, tag string) bool {
+	if header == "" {
+		return false
+	}
+
+	tag = strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == tag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/user"
+)
+
+// newCookie applies the configured cookie attributes. Secure cookies get the
+// __Host- prefix when they are host-only with path "/", and the __Secure-
+// prefix otherwise.
+func newCookie(r *http.Request, name string, value string, path string, expires time.Time, httpOnly bool) *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Expires:  expires,
+		HttpOnly: httpOnly,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	cnf, err := config.GetAppConfig()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+	} else {
+		cookie.Secure = cookie.Secure || cnf.GetCookieSecure()
+		cookie.Domain = cnf.GetCookieDomain()
+		switch cnf.GetCookieSameSite() {
+		case config.CookieSameSiteStrict:
+			cookie.SameSite = http.SameSiteStrictMode
+		case config.CookieSameSiteNone:
+			// Browsers drop SameSite=None cookies which are not Secure.
+			if cookie.Secure {
+				cookie.SameSite = http.SameSiteNoneMode
+			}
+		}
+	}
+
+	if cookie.Secure {
+		if cookie.Domain == "" && cookie.Path == "/" {
+			cookie.Name = auth.HostCookiePrefix + name
+		} else {
+			cookie.Name = auth.SecureCookiePrefix + name
+		}
+	}
+
+	return cookie
+}
+
+func expiredCookie(r *http.Request, name string, path string, httpOnly bool) *http.Cookie {
+	cookie := newCookie(r, name, "", path, time.Unix(0, 0), httpOnly)
+	cookie.MaxAge = -1
+
+	return cookie
+}
+
+// setAuthCookies stores the tokens for browsers together with a new CSRF
+// token, which the client must echo in the X-CSRF-Token header of every
+// state-changing request authenticated by the cookie.
+func setAuthCookies(w http.ResponseWriter, r *http.Request, tokens user.Tokens) {
+	SetAccessCookie(w, r, tokens)
+
+	refreshCookie := newCookie(r, refreshTokenCookie, tokens.RefreshToken, refreshTokenCookiePath, tokens.RefreshExpiresAt, true)
+	http.SetCookie(w, refreshCookie)
+
+	csrfToken, err := newCSRFToken()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed generate csrf token", args)
+		return
+	}
+
+	http.SetCookie(w, newCookie(r, auth.CSRFCookie, csrfToken, "/", tokens.RefreshExpiresAt, false))
+	w.Header().Set(auth.CSRFHeader, csrfToken)
+}
+
+// SetAccessCookie replaces only the access token cookie, as done by the
+// sliding renewal of cookie sessions.
+func SetAccessCookie(w http.ResponseWriter, r *http.Request, tokens user.Tokens) {
+	accessCookie := newCookie(r, auth.TokenCookie, tokens.AccessToken, "/", tokens.AccessExpiresAt, true)
+	r.AddCookie(accessCookie)
+	http.SetCookie(w, accessCookie)
+}
+
+func clearAuthCookies(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, expiredCookie(r, auth.TokenCookie, "/", true))
+	http.SetCookie(w, expiredCookie(r, refreshTokenCookie, refreshTokenCookiePath, true))
+	http.SetCookie(w, expiredCookie(r, auth.CSRFCookie, "/", false))
+}
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
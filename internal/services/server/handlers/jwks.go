@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/keyring"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+)
+
+func JWKSHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(keyring.PublicJWKS()); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error send jwks response", args)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package handlers
+
+import (
+	"expvar"
+	"net/http"
+)
+
+// MetricsHandler exposes the expvar metrics, including panic and worker
+// restart counters.
+func MetricsHandler() http.HandlerFunc {
+	return expvar.Handler().ServeHTTP
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/oidc"
+	"github.com/MagicNetLab/go-diploma/internal/services/user"
+)
+
+// OIDCLoginHandler redirects the browser to the identity provider. The login
+// state is kept in a cookie limited to the callback path.
+func OIDCLoginHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		login, err := user.StartOIDCLogin()
+		if err != nil {
+			if errors.Is(err, oidc.ErrorDisabled) {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail start oidc login", args)
+			http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+			return
+		}
+
+		// The provider redirects back with a top-level GET, so the state
+		// cookie must stay SameSite=Lax even when auth cookies are strict.
+		cookie := newCookie(r, oidcStateCookie, login.StateToken, oidcStateCookiePath, login.ExpiresAt, true)
+		cookie.SameSite = http.SameSiteLaxMode
+		http.SetCookie(w, cookie)
+
+		http.Redirect(w, r, login.URL, http.StatusFound)
+	}
+}
+
+func OIDCCallbackHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !oidc.Enabled() {
+			http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+			return
+		}
+
+		cookie, err := auth.Cookie(r, oidcStateCookie)
+		http.SetCookie(w, expiredCookie(r, oidcStateCookie, oidcStateCookiePath, true))
+		if err != nil {
+			logger.Error("failed oidc callback: state cookie is missing", nil)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		query := r.URL.Query()
+		if providerError := query.Get("error"); providerError != "" {
+			args := map[string]interface{}{"error": providerError, "description": query.Get("error_description")}
+			logger.Info("oidc login rejected by provider", args)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		tokens, err := user.CompleteOIDCLogin(cookie.Value, query.Get("state"), query.Get("code"), requestClient(r))
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail oidc login", args)
+
+			if WriteAccountStatusError(w, err) {
+				return
+			}
+
+			if errors.Is(err, user.ErrorInvalidOIDCState) {
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			if errors.Is(err, oidc.ErrorInvalidIDToken) || errors.Is(err, oidc.ErrorTokenExchange) {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		writeAuthResponse(w, r, tokens)
+	}
+}
@@ -7,17 +7,17 @@ import (
 	"net/http"
 	"strconv"
 
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
 	"github.com/MagicNetLab/go-diploma/internal/services/logger"
 	"github.com/MagicNetLab/go-diploma/internal/services/order"
-	"github.com/MagicNetLab/go-diploma/internal/services/user"
 )
 
 func CreateOrderHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID, err := user.GetAuthUserID(r)
+		userID, err := auth.UserID(r.Context())
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error()}
-			logger.Error("error getting auth user id from cookie", args)
+			logger.Error("error getting auth user id from request context", args)
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
@@ -26,6 +26,10 @@ func CreateOrderHandler() http.HandlerFunc {
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error()}
 			logger.Error("create order error: failed reading body", args)
+			if isBodyTooLarge(err) {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
@@ -81,10 +85,10 @@ func CreateOrderHandler() http.HandlerFunc {
 
 func OrderListHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID, err := user.GetAuthUserID(r)
+		userID, err := auth.UserID(r.Context())
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error()}
-			logger.Error("error getting auth user id from cookie", args)
+			logger.Error("error getting auth user id from request context", args)
 			w.Header().Set("content-type", "application/json")
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(http.StatusText(http.StatusUnauthorized)))
@@ -92,6 +96,21 @@ func OrderListHandler() http.HandlerFunc {
 			return
 		}
 
+		version, err := order.OrdersVersion(userID)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("error getting user orders version", args)
+			w.Header().Set("content-type", "application/json")
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(http.StatusText(http.StatusInternalServerError)))
+
+			return
+		}
+
+		if notModified(w, r, etag(userID, "orders", version)) {
+			return
+		}
+
 		userOrders, err := order.GetUserOrders(userID)
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error(), "userID": userID}
@@ -103,16 +122,7 @@ func OrderListHandler() http.HandlerFunc {
 			return
 		}
 
-		var response UserOrdersResponse
-		for _, userOrder := range userOrders {
-			o := UserOrder{
-				Number:     strconv.Itoa(userOrder.Number),
-				Status:     userOrder.Status,
-				Accrual:    userOrder.Accrual,
-				UploadedAt: userOrder.UploadedAt,
-			}
-			response = append(response, o)
-		}
+		response := newUserOrdersResponse(userOrders)
 
 		w.Header().Set("content-type", "application/json")
 		w.WriteHeader(http.StatusOK)
@@ -134,14 +144,26 @@ func OrderListHandler() http.HandlerFunc {
 
 func BalanceHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID, err := user.GetAuthUserID(r)
+		userID, err := auth.UserID(r.Context())
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error()}
-			logger.Error("error getting auth user id from cookie", args)
+			logger.Error("error getting auth user id from request context", args)
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
 
+		version, err := order.BalanceVersion(userID)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("error getting balance version", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		if notModified(w, r, etag(userID, "balance", version)) {
+			return
+		}
+
 		result, err := order.GetBalanceByUserID(userID)
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error(), "userID": userID}
@@ -167,10 +189,10 @@ func BalanceHandler() http.HandlerFunc {
 
 func WithdrawRequestHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID, err := user.GetAuthUserID(r)
+		userID, err := auth.UserID(r.Context())
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error()}
-			logger.Error("error getting auth user id from cookie", args)
+			logger.Error("error getting auth user id from request context", args)
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
@@ -179,7 +201,7 @@ func WithdrawRequestHandler() http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&withdrawRequest); err != nil {
 			args := map[string]interface{}{"error": err.Error()}
 			logger.Error("failed to decode withdraw request", args)
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeDecodeError(w, err)
 			return
 		}
 
@@ -230,30 +252,34 @@ func WithdrawRequestHandler() http.HandlerFunc {
 
 func WithdrawListHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		userID, err := user.GetAuthUserID(r)
+		userID, err := auth.UserID(r.Context())
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error()}
-			logger.Error("error getting auth user id from cookie", args)
+			logger.Error("error getting auth user id from request context", args)
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
 
-		result, err := order.GetWithdrawsByUserID(userID)
+		version, err := order.WithdrawalsVersion(userID)
 		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("error getting withdrawals version", args)
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
 
-		var response WithdrawResponse
-		for _, withdraw := range result {
-			w := UserWithdraw{
-				Order:       withdraw.Order,
-				Sum:         withdraw.Sum,
-				ProcessedAt: withdraw.ProcessedAt,
-			}
-			response = append(response, w)
+		if notModified(w, r, etag(userID, "withdrawals", version)) {
+			return
+		}
+
+		result, err := order.GetWithdrawsByUserID(userID)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
 		}
 
+		response := newWithdrawResponse(result)
+
 		w.Header().Set("content-type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		if err := json.NewEncoder(w).Encode(response); err != nil {
@@ -264,3 +290,32 @@ func WithdrawListHandler() http.HandlerFunc {
 		}
 	}
 }
+
+func newUserOrdersResponse(userOrders []order.Order) UserOrdersResponse {
+	var response UserOrdersResponse
+	for _, userOrder := range userOrders {
+		o := UserOrder{
+			Number:     strconv.Itoa(userOrder.Number),
+			Status:     userOrder.Status,
+			Accrual:    userOrder.Accrual,
+			UploadedAt: userOrder.UploadedAt,
+		}
+		response = append(response, o)
+	}
+
+	return response
+}
+
+func newWithdrawResponse(list order.WithdrawList) WithdrawResponse {
+	var response WithdrawResponse
+	for _, withdraw := range list {
+		w := UserWithdraw{
+			Order:       withdraw.Order,
+			Sum:         withdraw.Sum,
+			ProcessedAt: withdraw.ProcessedAt,
+		}
+		response = append(response, w)
+	}
+
+	return response
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/user"
+	"github.com/go-chi/chi/v5"
+)
+
+func SessionListHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.FromContext(r.Context())
+		if !ok {
+			logger.Error("fail list sessions: request is not authenticated", nil)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		sessions, err := user.ListSessions(principal.UserID, principal.SessionID)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": principal.UserID}
+			logger.Error("fail list user sessions", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		response := make(UserSessionsResponse, 0, len(sessions))
+		for _, s := range sessions {
+			response = append(response, UserSession{
+				ID:        s.ID,
+				IP:        s.IP,
+				UserAgent: s.UserAgent,
+				CreatedAt: s.CreatedAt,
+				LastSeen:  s.LastSeen,
+				Current:   s.Current,
+			})
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("Failed to write sessions response", args)
+		}
+	}
+}
+
+func RevokeSessionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.FromContext(r.Context())
+		if !ok {
+			logger.Error("fail revoke session: request is not authenticated", nil)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		sessionID := chi.URLParam(r, "id")
+		if sessionID == "" {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err := user.RevokeSession(principal.UserID, sessionID)
+		if err != nil {
+			if errors.Is(err, user.ErrorSessionNotFound) {
+				http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+
+			args := map[string]interface{}{"error": err.Error(), "userID": principal.UserID}
+			logger.Error("fail revoke user session", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		if sessionID == principal.SessionID {
+			clearAuthCookies(w, r)
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// RevokeSessionsHandler signs the user out of all sessions except the one
+// making the request.
+func RevokeSessionsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.FromContext(r.Context())
+		if !ok {
+			logger.Error("fail revoke sessions: request is not authenticated", nil)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		count, err := user.RevokeOtherSessions(principal.UserID, principal.SessionID)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": principal.UserID}
+			logger.Error("fail revoke user sessions", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(RevokeSessionsResponse{Revoked: count}); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("Failed to write revoke sessions response", args)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/export"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/order"
+)
+
+const statementDateLayout = "2006-01-02"
+
+// StatementHandler returns the current user's balance movements with the
+// running balance. The period is set by the from and to query parameters,
+// pages by limit and offset.
+func StatementHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := auth.UserID(r.Context())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error getting auth user id from request context", args)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		from, to, err := statementPeriod(query.Get("from"), query.Get("to"))
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "invalid_period", err.Error())
+			return
+		}
+
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		offset, _ := strconv.Atoi(query.Get("offset"))
+
+		entries, err := order.GetStatement(userID, from, to, limit, offset)
+		if err != nil {
+			if errors.Is(err, order.ErrorInvalidStatementPeriod) {
+				WriteError(w, http.StatusBadRequest, "invalid_period", err.Error())
+				return
+			}
+
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("error getting user statement", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		response := make(StatementResponse, 0, len(entries))
+		for _, e := range entries {
+			response = append(response, StatementEntry{
+				Type:       e.Type,
+				Reference:  e.Reference,
+				Amount:     e.Amount,
+				Balance:    e.Balance,
+				OccurredAt: e.OccurredAt,
+			})
+		}
+
+		w.Header().Set("Cache-Control", "no-store")
+		writeJSON(w, http.StatusOK, response)
+	}
+}
+
+// StatementExportHandler streams the statement of the period as a CSV or
+// PDF document with the opening and closing balance.
+func StatementExportHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := auth.UserID(r.Context())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error getting auth user id from request context", args)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		query := r.URL.Query()
+		format := query.Get("format")
+		writer, err := export.NewWriter(format, w)
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "invalid_format", err.Error())
+			return
+		}
+
+		from, to, err := statementPeriod(query.Get("from"), query.Get("to"))
+		if err != nil {
+			WriteError(w, http.StatusBadRequest, "invalid_period", err.Error())
+			return
+		}
+
+		// Formats built in memory are refused up front, while the response
+		// can still carry an error.
+		if maxEntries := export.MaxEntries(format); maxEntries > 0 {
+			count, err := order.CountStatementEntries(userID, from, to)
+			if err != nil {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+
+			if count > maxEntries {
+				message := fmt.Sprintf("the %s statement is limited to %d entries, choose a shorter period or the %s format",
+					format, maxEntries, export.FormatCSV)
+				WriteError(w, http.StatusBadRequest, "statement_too_large", message)
+				return
+			}
+		}
+
+		opening, err := order.GetOpeningBalance(userID, from)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		// A long statement takes longer to send than the server write timeout
+		// allows, so the deadline follows the export timeout instead.
+		err = http.NewResponseController(w).SetWriteDeadline(time.Now().Add(order.StatementExportTimeout))
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("failed extend statement export write deadline", args)
+		}
+
+		w.Header().Set("content-type", export.ContentType(format))
+		w.Header().Set("Content-Disposition", `attachment; filename="statement.`+format+`"`)
+		w.Header().Set("Cache-Control", "no-store")
+
+		summary := export.Summary{
+			From:           query.Get("from"),
+			To:             query.Get("to"),
+			GeneratedAt:    time.Now().UTC().Format(time.RFC3339),
+			OpeningBalance: opening,
+		}
+
+		closing := opening
+		err = writer.Begin(summary)
+		if err == nil {
+			err = order.EachStatementEntry(userID, from, to, func(e order.StatementEntry) error {
+				closing = e.Balance
+				return writer.Entry(export.Entry{
+					OccurredAt: e.OccurredAt,
+					Type:       e.Type,
+					Reference:  e.Reference,
+					Amount:     e.Amount,
+					Balance:    e.Balance,
+				})
+			})
+		}
+		if err == nil {
+			err = writer.End(closing)
+		}
+
+		if err != nil {
+			// Part of the document may already be sent, so the connection is
+			// dropped rather than letting the client keep a truncated file.
+			args := map[string]interface{}{"error": err.Error(), "userID": userID, "format": format}
+			logger.Error("error export user statement", args)
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+// statementPeriod parses the period bounds. Both accept RFC 3339 time or a
+// date; a date in "to" includes the whole day.
+func statementPeriod(fromParam string, toParam string) (*time.Time, *time.Time, error) {
+	from, err := parseStatementTime(fromParam, false)
+	if err != nil {
+		return nil, nil, errors.New("invalid from parameter")
+	}
+
+	to, err := parseStatementTime(toParam, true)
+	if err != nil {
+		return nil, nil, errors.New("invalid to parameter")
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, order.ErrorInvalidStatementPeriod
+	}
+
+	return from, to, nil
+}
+
+func parseStatementTime(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(statementDateLayout, value); err == nil {
+		if endOfDay {
+			t = t.AddDate(0, 0, 1)
+		}
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+
+	t = t.UTC()
+	return &t, nil
+}
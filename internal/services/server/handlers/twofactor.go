@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/user"
+)
+
+func SecondFactorLoginHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var loginRequest SecondFactorLoginRequest
+		if err := json.NewDecoder(r.Body).Decode(&loginRequest); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error decoding second factor login request body", args)
+			writeDecodeError(w, err)
+			return
+		}
+
+		if !loginRequest.IsValid() {
+			logger.Error("failed second factor login: one or more request params is empty", nil)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		tokens, err := user.CompleteLogin(loginRequest.Challenge, loginRequest.Code, requestClient(r))
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail second factor login", args)
+
+			var throttled *user.ThrottledError
+			if errors.As(err, &throttled) {
+				writeThrottled(w, throttled)
+				return
+			}
+
+			if WriteAccountStatusError(w, err) {
+				return
+			}
+
+			if errors.Is(err, user.ErrorInvalidChallenge) || errors.Is(err, user.ErrorInvalidSecondFactor) {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		writeAuthResponse(w, r, tokens)
+	}
+}
+
+func TOTPEnrollHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := auth.UserID(r.Context())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error getting auth user id from request context", args)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		enrollment, err := user.EnrollTOTP(userID)
+		if err != nil {
+			if errors.Is(err, user.ErrorTOTPAlreadyEnabled) {
+				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+				return
+			}
+
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("fail enroll totp", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		response := TOTPEnrollResponse{Secret: enrollment.Secret, URI: enrollment.URI}
+
+		w.Header().Set("content-type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("Failed to write totp enroll response", args)
+		}
+	}
+}
+
+func TOTPConfirmHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := auth.UserID(r.Context())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error getting auth user id from request context", args)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		var confirmRequest TOTPConfirmRequest
+		if err := json.NewDecoder(r.Body).Decode(&confirmRequest); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error decoding totp confirm request body", args)
+			writeDecodeError(w, err)
+			return
+		}
+
+		if !confirmRequest.IsValid() {
+			logger.Error("failed totp confirm: code is empty", nil)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		codes, err := user.ConfirmTOTP(userID, confirmRequest.Code)
+		if err != nil {
+			switch {
+			case errors.Is(err, user.ErrorTOTPAlreadyEnabled):
+				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
+			case errors.Is(err, user.ErrorTOTPNotEnrolled), errors.Is(err, user.ErrorInvalidSecondFactor):
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			default:
+				args := map[string]interface{}{"error": err.Error(), "userID": userID}
+				logger.Error("fail confirm totp", args)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("content-type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(TOTPConfirmResponse{RecoveryCodes: codes}); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("Failed to write totp confirm response", args)
+		}
+	}
+}
+
+// writeSecondFactorRequired answers the first login step of users with
+// two-factor authentication.
+func writeSecondFactorRequired(w http.ResponseWriter, secondFactor *user.SecondFactorRequiredError) {
+	response := SecondFactorResponse{
+		MFARequired: true,
+		Challenge:   secondFactor.Challenge,
+		ExpiresAt:   secondFactor.ExpiresAt,
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("Failed to write second factor response", args)
+	}
+}
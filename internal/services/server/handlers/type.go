@@ -1,14 +1,41 @@
 package handlers
 
+import "time"
+
+const (
+	refreshTokenCookie     = "refresh_token"
+	refreshTokenCookiePath = "/api/user/"
+	oidcStateCookie        = "oidc_state"
+	oidcStateCookiePath    = "/api/user/oidc/"
+)
+
 type RegisterUserRequest struct {
 	Login    string `json:"login,omitempty"`
 	Password string `json:"password,omitempty"`
+	Email    string `json:"email,omitempty"`
 }
 
 func (r *RegisterUserRequest) IsValid() bool {
 	return r.Login != "" && r.Password != ""
 }
 
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ErrorResponse carries a machine readable error code for errors the
+// client is expected to handle.
+type ErrorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message"`
+}
+
+type ValidationErrorResponse struct {
+	Errors []FieldError `json:"errors"`
+}
+
 type UserLoginRequest struct {
 	Login    string `json:"login,omitempty"`
 	Password string `json:"password,omitempty"`
@@ -18,6 +45,93 @@ func (r *UserLoginRequest) IsValid() bool {
 	return r.Login != "" && r.Password != ""
 }
 
+type AuthTokenResponse struct {
+	Token            string    `json:"token"`
+	TokenType        string    `json:"token_type"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
+}
+
+// SecondFactorResponse is returned by login instead of tokens when the user
+// has two-factor authentication enabled.
+type SecondFactorResponse struct {
+	MFARequired bool      `json:"mfa_required"`
+	Challenge   string    `json:"challenge"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type SecondFactorLoginRequest struct {
+	Challenge string `json:"challenge,omitempty"`
+	Code      string `json:"code,omitempty"`
+}
+
+func (r *SecondFactorLoginRequest) IsValid() bool {
+	return r.Challenge != "" && r.Code != ""
+}
+
+type TOTPEnrollResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type TOTPConfirmRequest struct {
+	Code string `json:"code,omitempty"`
+}
+
+func (r *TOTPConfirmRequest) IsValid() bool {
+	return r.Code != ""
+}
+
+type TOTPConfirmResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type UserSession struct {
+	ID        string    `json:"id"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	LastSeen  time.Time `json:"last_seen"`
+	Current   bool      `json:"current"`
+}
+
+type UserSessionsResponse []UserSession
+
+type RevokeSessionsResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password,omitempty"`
+	NewPassword     string `json:"new_password,omitempty"`
+}
+
+func (r *ChangePasswordRequest) IsValid() bool {
+	return r.CurrentPassword != "" && r.NewPassword != ""
+}
+
+type PasswordResetRequest struct {
+	Login string `json:"login,omitempty"`
+}
+
+func (r *PasswordResetRequest) IsValid() bool {
+	return r.Login != ""
+}
+
+type PasswordResetConfirmRequest struct {
+	Token    string `json:"token,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+func (r *PasswordResetConfirmRequest) IsValid() bool {
+	return r.Token != "" && r.Password != ""
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
 type WithDrawRequest struct {
 	Order string  `json:"order,omitempty"`
 	Sum   float64 `json:"sum,omitempty"`
@@ -48,3 +162,107 @@ type UserWithdraw struct {
 }
 
 type WithdrawResponse []UserWithdraw
+
+type AdminUnlockUserRequest struct {
+	Login string `json:"login,omitempty"`
+}
+
+func (r *AdminUnlockUserRequest) IsValid() bool {
+	return r.Login != ""
+}
+
+type AdminUser struct {
+	ID          int    `json:"id"`
+	Login       string `json:"login"`
+	Email       string `json:"email,omitempty"`
+	Role        string `json:"role"`
+	Status      string `json:"status"`
+	TOTPEnabled bool   `json:"totp_enabled"`
+	// LoginConflict is set for accounts left with a case-insensitive
+	// duplicate login by the login normalization.
+	LoginConflict bool `json:"login_conflict,omitempty"`
+}
+
+type AdminUsersResponse []AdminUser
+
+type AdminSetRoleRequest struct {
+	Role string `json:"role,omitempty"`
+}
+
+func (r *AdminSetRoleRequest) IsValid() bool {
+	return r.Role != ""
+}
+
+type AdminAdjustmentRequest struct {
+	Type       string  `json:"type,omitempty"`
+	Amount     float64 `json:"amount,omitempty"`
+	ReasonCode string  `json:"reason_code,omitempty"`
+	Comment    string  `json:"comment,omitempty"`
+}
+
+func (r *AdminAdjustmentRequest) IsValid() bool {
+	return r.Type != "" && r.Amount > 0 && r.ReasonCode != "" && r.Comment != ""
+}
+
+type AdminAdjustment struct {
+	ID         int     `json:"id"`
+	UserID     int     `json:"user_id"`
+	Type       string  `json:"type"`
+	Amount     float64 `json:"amount"`
+	ReasonCode string  `json:"reason_code"`
+	Comment    string  `json:"comment"`
+	Status     string  `json:"status"`
+	CreatedBy  int     `json:"created_by"`
+	DecidedBy  int     `json:"decided_by,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+	DecidedAt  string  `json:"decided_at,omitempty"`
+}
+
+type AdminAdjustmentsResponse []AdminAdjustment
+
+type UserAdjustment struct {
+	Type        string  `json:"type"`
+	Amount      float64 `json:"amount"`
+	ReasonCode  string  `json:"reason_code"`
+	ProcessedAt string  `json:"processed_at"`
+}
+
+type UserAdjustmentsResponse []UserAdjustment
+
+type StatementEntry struct {
+	Type       string  `json:"type"`
+	Reference  string  `json:"reference"`
+	Amount     float64 `json:"amount"`
+	Balance    float64 `json:"balance"`
+	OccurredAt string  `json:"occurred_at"`
+}
+
+type StatementResponse []StatementEntry
+
+type CreateAPIKeyRequest struct {
+	Name          string   `json:"name,omitempty"`
+	Scopes        []string `json:"scopes,omitempty"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty"`
+}
+
+func (r *CreateAPIKeyRequest) IsValid() bool {
+	return r.Name != "" && len(r.Scopes) > 0
+}
+
+type APIKeyResponse struct {
+	ID         int        `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// CreateAPIKeyResponse is the only response which contains the key itself.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type APIKeysResponse []APIKeyResponse
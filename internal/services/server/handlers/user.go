@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
-	"time"
+	"strconv"
 
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
 	"github.com/MagicNetLab/go-diploma/internal/services/logger"
 	"github.com/MagicNetLab/go-diploma/internal/services/user"
 )
@@ -17,7 +20,7 @@ func UserRegisterHandler() http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&regRequest); err != nil {
 			args := map[string]interface{}{"error": err.Error()}
 			logger.Error("error decoding register request body", args)
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeDecodeError(w, err)
 			return
 		}
 
@@ -27,8 +30,14 @@ func UserRegisterHandler() http.HandlerFunc {
 			return
 		}
 
-		err := user.Register(regRequest.Login, regRequest.Password)
+		err := user.Register(regRequest.Login, regRequest.Password, regRequest.Email)
 		if err != nil {
+			var validationErrors user.ValidationErrors
+			if errors.As(err, &validationErrors) {
+				writeValidationErrors(w, validationErrors)
+				return
+			}
+
 			if errors.Is(err, user.ErrorUserExists) {
 				logger.Error(fmt.Sprintf("failed register user: login %s is exists", regRequest.Login), nil)
 				http.Error(w, http.StatusText(http.StatusConflict), http.StatusConflict)
@@ -41,24 +50,24 @@ func UserRegisterHandler() http.HandlerFunc {
 			return
 		}
 
-		token, err := user.Login(regRequest.Login, regRequest.Password)
+		tokens, err := user.Login(regRequest.Login, regRequest.Password, requestClient(r))
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error(), "login": regRequest.Login}
 			logger.Error("fail user login", args)
+
+			// The account exists, but the client IP is out of login attempts;
+			// the client logs in once the limit expires.
+			var throttled *user.ThrottledError
+			if errors.As(err, &throttled) {
+				writeThrottled(w, throttled)
+				return
+			}
+
 			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 			return
 		}
 
-		newCookie := http.Cookie{Name: "token", Value: token, Path: "/", Expires: time.Now().Add(time.Minute * 60)}
-		r.AddCookie(&newCookie)
-		http.SetCookie(w, &newCookie)
-
-		w.WriteHeader(http.StatusOK)
-		_, err = w.Write([]byte("OK"))
-		if err != nil {
-			args := map[string]interface{}{"error": err.Error()}
-			logger.Error("Failed to write register user response response", args)
-		}
+		writeAuthResponse(w, r, tokens)
 	}
 }
 
@@ -68,7 +77,7 @@ func UserLoginHandler() http.HandlerFunc {
 		if err := json.NewDecoder(r.Body).Decode(&loginRequest); err != nil {
 			args := map[string]interface{}{"error": err.Error()}
 			logger.Error("error decoding login request body", args)
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeDecodeError(w, err)
 			return
 		}
 
@@ -78,23 +87,300 @@ func UserLoginHandler() http.HandlerFunc {
 			return
 		}
 
-		token, err := user.Login(loginRequest.Login, loginRequest.Password)
+		tokens, err := user.Login(loginRequest.Login, loginRequest.Password, requestClient(r))
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error(), "login": loginRequest.Login}
 			logger.Error("fail user login", args)
+
+			if WriteAccountStatusError(w, err) {
+				return
+			}
+
+			var secondFactor *user.SecondFactorRequiredError
+			if errors.As(err, &secondFactor) {
+				writeSecondFactorRequired(w, secondFactor)
+				return
+			}
+
+			var throttled *user.ThrottledError
+			if errors.As(err, &throttled) {
+				writeThrottled(w, throttled)
+				return
+			}
+
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		writeAuthResponse(w, r, tokens)
+	}
+}
+
+func RefreshTokenHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var refreshToken string
+		if cookie, err := auth.Cookie(r, refreshTokenCookie); err == nil {
+			if !auth.ValidCSRF(r) {
+				logger.Error("failed refresh token: csrf token mismatch", nil)
+				WriteError(w, http.StatusForbidden, "csrf_token_invalid", "missing or invalid "+auth.CSRFHeader+" header")
+				return
+			}
+			refreshToken = cookie.Value
+		} else {
+			var refreshRequest RefreshTokenRequest
+			if err := json.NewDecoder(r.Body).Decode(&refreshRequest); err != nil {
+				args := map[string]interface{}{"error": err.Error()}
+				logger.Error("error decoding refresh token request body", args)
+				writeDecodeError(w, err)
+				return
+			}
+			refreshToken = refreshRequest.RefreshToken
+		}
+
+		if refreshToken == "" {
+			logger.Error("failed refresh token: refresh token is empty", nil)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		tokens, err := user.Refresh(refreshToken)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail refresh token", args)
+
+			if errors.Is(err, user.ErrorInvalidRefreshToken) || errors.Is(err, user.ErrorRefreshTokenReused) {
+				clearAuthCookies(w, r)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		writeAuthResponse(w, r, tokens)
+	}
+}
+
+func LogoutHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := auth.FromContext(r.Context())
+		if !ok {
+			logger.Error("fail user logout: request is not authenticated", nil)
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
 			return
 		}
 
-		newCookie := http.Cookie{Name: "token", Value: token, Path: "/", Expires: time.Now().Add(time.Minute * 60)}
-		r.AddCookie(&newCookie)
-		http.SetCookie(w, &newCookie)
+		err := user.Logout(principal)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail user logout", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		clearAuthCookies(w, r)
 
 		w.WriteHeader(http.StatusOK)
 		_, err = w.Write([]byte("OK"))
 		if err != nil {
 			args := map[string]interface{}{"error": err.Error()}
-			logger.Error("Failed to write response", args)
+			logger.Error("Failed to write logout response", args)
+		}
+	}
+}
+
+// writeAuthResponse returns issued tokens in cookies for browsers and in the
+// Authorization header and JSON body for API clients.
+func writeAuthResponse(w http.ResponseWriter, r *http.Request, tokens user.Tokens) {
+	setAuthCookies(w, r, tokens)
+
+	response := AuthTokenResponse{
+		Token:            tokens.AccessToken,
+		TokenType:        "Bearer",
+		ExpiresAt:        tokens.AccessExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
+	}
+
+	w.Header().Set("Authorization", "Bearer "+tokens.AccessToken)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("Failed to write auth response", args)
+	}
+}
+
+func ChangePasswordHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := auth.UserID(r.Context())
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error getting auth user id from request context", args)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		var passwordRequest ChangePasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&passwordRequest); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error decoding change password request body", args)
+			writeDecodeError(w, err)
+			return
+		}
+
+		if !passwordRequest.IsValid() {
+			logger.Error("failed change password: one or more request params is empty", nil)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		tokens, err := user.ChangePassword(userID, passwordRequest.CurrentPassword, passwordRequest.NewPassword, requestClient(r))
+		if err != nil {
+			var validationErrors user.ValidationErrors
+			if errors.As(err, &validationErrors) {
+				writeValidationErrors(w, validationErrors)
+				return
+			}
+
+			var throttled *user.ThrottledError
+			if errors.As(err, &throttled) {
+				writeThrottled(w, throttled)
+				return
+			}
+
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("fail change user password", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		writeAuthResponse(w, r, tokens)
+	}
+}
+
+func PasswordResetHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var resetRequest PasswordResetRequest
+		if err := json.NewDecoder(r.Body).Decode(&resetRequest); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error decoding password reset request body", args)
+			writeDecodeError(w, err)
+			return
+		}
+
+		if !resetRequest.IsValid() {
+			logger.Error("failed password reset: login is empty", nil)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err := user.RequestPasswordReset(resetRequest.Login, requestClient(r))
+		if err != nil {
+			var throttled *user.ThrottledError
+			if errors.As(err, &throttled) {
+				writeThrottled(w, throttled)
+				return
+			}
+
+			args := map[string]interface{}{"error": err.Error(), "login": resetRequest.Login}
+			logger.Error("fail request password reset", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("Accepted"))
+	}
+}
+
+func PasswordResetConfirmHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var confirmRequest PasswordResetConfirmRequest
+		if err := json.NewDecoder(r.Body).Decode(&confirmRequest); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("error decoding password reset confirm request body", args)
+			writeDecodeError(w, err)
+			return
+		}
+
+		if !confirmRequest.IsValid() {
+			logger.Error("failed password reset confirm: one or more request params is empty", nil)
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		err := user.ResetPassword(confirmRequest.Token, confirmRequest.Password)
+		if err != nil {
+			var validationErrors user.ValidationErrors
+			if errors.As(err, &validationErrors) {
+				writeValidationErrors(w, validationErrors)
+				return
+			}
+
+			if errors.Is(err, user.ErrorInvalidResetToken) {
+				logger.Error("failed password reset confirm: invalid token", nil)
+				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+				return
+			}
+
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("fail reset password", args)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
 		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("OK"))
+	}
+}
+
+// writeDecodeError answers a request whose body could not be decoded: with
+// 413 when the body exceeds the route limit and with 400 otherwise.
+func writeDecodeError(w http.ResponseWriter, err error) {
+	if isBodyTooLarge(err) {
+		http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
+
+func isBodyTooLarge(err error) bool {
+	var maxBytesErr *http.MaxBytesError
+	return errors.As(err, &maxBytesErr)
+}
+
+func writeValidationErrors(w http.ResponseWriter, validationErrors user.ValidationErrors) {
+	var response ValidationErrorResponse
+	for _, v := range validationErrors {
+		response.Errors = append(response.Errors, FieldError{Field: v.Field, Code: v.Code, Message: v.Message})
 	}
+
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("Failed to write validation errors response", args)
+	}
+}
+
+func writeThrottled(w http.ResponseWriter, throttled *user.ThrottledError) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+	http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+}
+
+func requestClient(r *http.Request) user.Client {
+	return user.Client{IP: clientIP(r), UserAgent: r.UserAgent()}
+}
+
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
 }
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"net/http"
+)
+
+const (
+	// authBodyLimit covers login, registration and password requests, which
+	// are a few short JSON fields.
+	authBodyLimit = 4 << 10
+	// orderBodyLimit covers the plain text order number.
+	orderBodyLimit = 1 << 10
+)
+
+type bodyLimitKey struct{}
+
+// maxBodySize is the default request body limit, set from the config in Run.
+var maxBodySize int64 = 1 << 20
+
+// mwBodyLimit overrides the default body limit of the route.
+func mwBodyLimit(limit int64) func(h http.HandlerFunc) http.HandlerFunc {
+	return func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), bodyLimitKey{}, limit)))
+		}
+	}
+}
+
+// mwLimitBody caps the request body at the route limit. mwDefault applies it
+// on both sides of the decompression, so a small compressed body cannot
+// expand beyond the limit either. Reads past the limit fail with
+// *http.MaxBytesError, which handlers answer with 413.
+func mwLimitBody(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := bodyLimit(r)
+		if r.ContentLength > limit {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = http.MaxBytesReader(w, r.Body, limit)
+		}
+
+		h.ServeHTTP(w, r)
+	}
+}
+
+func bodyLimit(r *http.Request) int64 {
+	if limit, ok := r.Context().Value(bodyLimitKey{}).(int64); ok {
+		return limit
+	}
+
+	return maxBodySize
+}
+
+// withBodyLimit returns a copy of the middleware list with the body limit of
+// the route, leaving the shared list untouched.
+func withBodyLimit(list mwList, limit int64) mwList {
+	return append(append(mwList{}, list...), mwBodyLimit(limit))
+}
@@ -1,27 +1,48 @@
 package server
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
 	"github.com/MagicNetLab/go-diploma/internal/services/compression"
 	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/server/handlers"
 	"github.com/MagicNetLab/go-diploma/internal/services/user"
 )
 
 type mwList []func(handlerFunc http.HandlerFunc) http.HandlerFunc
 
+var authenticator auth.Authenticator = auth.Chain{}
+
+// apiKeyAuthenticator additionally accepts API keys. It is used only on
+// routes which declare the scope a key needs.
+var apiKeyAuthenticator auth.Authenticator = auth.Chain{}
+
+var mwPublicGet = mwList{mwDefault, mwGet}
+var mwPublicPost = mwList{mwDefault, mwPost}
 var mwGuestGet = mwList{mwDefault, mwGet, mwGuest}
 var mwGuestPost = mwList{mwDefault, mwPost, mwGuest}
 var mwAuthorizedGet = mwList{mwDefault, mwGet, mwAuthorized}
 var mwAuthorizedPost = mwList{mwDefault, mwPost, mwAuthorized}
+var mwAuthorizedDelete = mwList{mwDefault, mwDelete, mwAuthorized}
+var mwOrdersReadGet = mwList{mwDefault, mwGet, mwScope(auth.ScopeOrdersRead)}
+var mwOrdersWritePost = mwList{mwDefault, mwPost, mwScope(auth.ScopeOrdersWrite)}
+var mwBalanceReadGet = mwList{mwDefault, mwGet, mwScope(auth.ScopeBalanceRead)}
+var mwBalanceWithdrawPost = mwList{mwDefault, mwPost, mwScope(auth.ScopeBalanceWithdraw)}
+var mwSupportGet = mwList{mwDefault, mwGet, mwRole(auth.RoleSupport), mwAuthorized, mwClientCert}
+var mwSupportPost = mwList{mwDefault, mwPost, mwRole(auth.RoleSupport), mwAuthorized, mwClientCert}
+var mwAdminGet = mwList{mwDefault, mwGet, mwRole(auth.RoleAdmin), mwAuthorized, mwClientCert}
+var mwAdminPost = mwList{mwDefault, mwPost, mwRole(auth.RoleAdmin), mwAuthorized, mwClientCert}
+var mwAdminDelete = mwList{mwDefault, mwDelete, mwRole(auth.RoleAdmin), mwAuthorized, mwClientCert}
 
 func mwDefault(h http.HandlerFunc) http.HandlerFunc {
-	return compression.GzipMiddleware(logger.Middleware(h))
+	return mwLimitBody(compression.Middleware(mwLimitBody(logger.Middleware(h))))
 }
 
 func mwGuest(h http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if user.CheckAuthorize(r) {
+		if _, err := authenticator.Authenticate(r); err == nil {
 			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
 			return
 		}
@@ -30,16 +51,99 @@ func mwGuest(h http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// mwAuthorized verifies the request credential once and stores the principal
+// in the request context for the handlers. Blocked and deleted accounts are
+// rejected even with a valid credential.
 func mwAuthorized(h http.HandlerFunc) http.HandlerFunc {
+	return authorize(h, authenticator, "")
+}
+
+// mwScope works as mwAuthorized, but also accepts API keys having the scope.
+func mwScope(scope string) func(h http.HandlerFunc) http.HandlerFunc {
+	return func(h http.HandlerFunc) http.HandlerFunc {
+		return authorize(h, apiKeyAuthenticator, scope)
+	}
+}
+
+func authorize(h http.HandlerFunc, a auth.Authenticator, scope string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !user.CheckAuthorize(r) {
+		principal, err := a.Authenticate(r)
+		if err != nil {
 			w.Header().Set("content-type", "application/json")
 			w.WriteHeader(http.StatusUnauthorized)
 			w.Write([]byte(http.StatusText(http.StatusUnauthorized)))
 			return
 		}
 
-		h.ServeHTTP(w, r)
+		// Browsers attach cookies to cross-site requests, so state-changing
+		// requests authenticated by the cookie must prove they come from our
+		// pages. Bearer tokens and API keys are never sent automatically.
+		if principal.Credential == auth.CredentialCookie && !auth.IsSafeMethod(r.Method) && !auth.ValidCSRF(r) {
+			args := map[string]interface{}{"userID": principal.UserID, "path": r.URL.Path}
+			logger.Security("csrf_rejected", args)
+			handlers.WriteError(w, http.StatusForbidden, "csrf_token_invalid", "missing or invalid "+auth.CSRFHeader+" header")
+			return
+		}
+
+		if scope != "" && !principal.HasScope(scope) {
+			handlers.WriteError(w, http.StatusForbidden, "insufficient_scope", "api key has no scope "+scope)
+			return
+		}
+
+		if err = user.CheckStatus(principal.UserID); err != nil {
+			if errors.Is(err, user.ErrorUserNotFound) {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			if !handlers.WriteAccountStatusError(w, err) {
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}
+			return
+		}
+
+		renewToken(w, r, principal)
+
+		h.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+	}
+}
+
+// renewToken reissues the token cookie of an active cookie session which is
+// about to expire. A failed renewal does not fail the request, the client
+// can still use the refresh token.
+func renewToken(w http.ResponseWriter, r *http.Request, principal auth.Principal) {
+	tokens, renewed, err := user.RenewToken(principal)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": principal.UserID}
+		logger.Error("failed renew session token", args)
+		return
+	}
+
+	if renewed {
+		handlers.SetAccessCookie(w, r, tokens)
+	}
+}
+
+// mwRole allows only principals having the role. It must run after
+// mwAuthorized, which puts the principal into the request context.
+func mwRole(role string) func(h http.HandlerFunc) http.HandlerFunc {
+	return func(h http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			if !principal.HasRole(role) {
+				args := map[string]interface{}{"userID": principal.UserID, "role": role, "path": r.URL.Path}
+				logger.Security("access_denied", args)
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+
+			h.ServeHTTP(w, r)
+		}
 	}
 }
 
@@ -65,6 +169,17 @@ func mwPost(h http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+func mwDelete(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	}
+}
+
 func mw(h http.HandlerFunc, mw mwList) http.HandlerFunc {
 	f := h
 	for _, m := range mw {
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/crash"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// validRequestID limits ids accepted from clients, as they end up in logs.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// problem is an RFC 7807 error response.
+type problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Instance  string `json:"instance,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// mwRequestID takes the request id from the X-Request-ID header or generates
+// one, and returns it in the response header.
+func mwRequestID(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID.MatchString(id) {
+			b := make([]byte, 8)
+			if _, err := rand.Read(b); err != nil {
+				args := map[string]interface{}{"error": err.Error()}
+				logger.Error("failed generate request id", args)
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			id = hex.EncodeToString(b)
+		}
+
+		w.Header().Set(requestIDHeader, id)
+		h.ServeHTTP(w, r.WithContext(logger.WithRequestID(r.Context(), id)))
+	})
+}
+
+// mwRecover turns a handler panic into a crash report and a 500 problem
+// response. When the response has already been started it aborts the
+// connection instead, so the client does not take a truncated body for a
+// complete one.
+func mwRecover(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &recoverResponseWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+
+			// ErrAbortHandler is the way to abort a response on purpose.
+			if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(v)
+			}
+
+			requestID := logger.RequestID(r.Context())
+			crash.Record(crash.Report{Source: "http", RequestID: requestID, Method: r.Method, Path: r.URL.Path}, v)
+
+			if rw.started {
+				panic(http.ErrAbortHandler)
+			}
+
+			w.Header().Del("Content-Encoding")
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(problem{
+				Type:      "about:blank",
+				Title:     http.StatusText(http.StatusInternalServerError),
+				Status:    http.StatusInternalServerError,
+				Instance:  r.URL.Path,
+				RequestID: requestID,
+			})
+		}()
+
+		h.ServeHTTP(rw, r)
+	})
+}
+
+type recoverResponseWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *recoverResponseWriter) WriteHeader(statusCode int) {
+	w.started = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *recoverResponseWriter) Write(b []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *recoverResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *recoverResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.started = true
+		f.Flush()
+	}
+}
@@ -7,13 +7,56 @@ import (
 
 func getRoute() *chi.Mux {
 	r := chi.NewRouter()
-	r.Post("/api/user/register", mw(handlers.UserRegisterHandler(), mwGuestPost))
-	r.Post("/api/user/login", mw(handlers.UserLoginHandler(), mwGuestPost))
-	r.Post("/api/user/orders", mw(handlers.CreateOrderHandler(), mwAuthorizedPost))
-	r.Get("/api/user/orders", mw(handlers.OrderListHandler(), mwAuthorizedGet))
-	r.Get("/api/user/balance", mw(handlers.BalanceHandler(), mwAuthorizedGet))
-	r.Post("/api/user/balance/withdraw", mw(handlers.WithdrawRequestHandler(), mwAuthorizedPost))
-	r.Get("/api/user/withdrawals", mw(handlers.WithdrawListHandler(), mwAuthorizedGet))
+	r.Use(mwRequestID, mwRecover)
+	r.Get("/.well-known/jwks.json", mw(handlers.JWKSHandler(), mwPublicGet))
+	r.Post("/api/user/register", mw(handlers.UserRegisterHandler(), withBodyLimit(mwGuestPost, authBodyLimit)))
+	r.Post("/api/user/login", mw(handlers.UserLoginHandler(), withBodyLimit(mwGuestPost, authBodyLimit)))
+	r.Post("/api/user/login/2fa", mw(handlers.SecondFactorLoginHandler(), withBodyLimit(mwGuestPost, authBodyLimit)))
+	r.Get("/api/user/oidc/login", mw(handlers.OIDCLoginHandler(), mwGuestGet))
+	r.Get("/api/user/oidc/callback", mw(handlers.OIDCCallbackHandler(), mwGuestGet))
+	r.Post("/api/user/token/refresh", mw(handlers.RefreshTokenHandler(), withBodyLimit(mwPublicPost, authBodyLimit)))
+	r.Post("/api/user/logout", mw(handlers.LogoutHandler(), mwAuthorizedPost))
+	r.Get("/api/user/api-keys", mw(handlers.APIKeyListHandler(), mwAuthorizedGet))
+	r.Post("/api/user/api-keys", mw(handlers.CreateAPIKeyHandler(), mwAuthorizedPost))
+	r.Delete("/api/user/api-keys/{id}", mw(handlers.RevokeAPIKeyHandler(), mwAuthorizedDelete))
+	r.Get("/api/user/sessions", mw(handlers.SessionListHandler(), mwAuthorizedGet))
+	r.Delete("/api/user/sessions", mw(handlers.RevokeSessionsHandler(), mwAuthorizedDelete))
+	r.Delete("/api/user/sessions/{id}", mw(handlers.RevokeSessionHandler(), mwAuthorizedDelete))
+	r.Post("/api/user/2fa/enroll", mw(handlers.TOTPEnrollHandler(), mwAuthorizedPost))
+	r.Post("/api/user/2fa/confirm", mw(handlers.TOTPConfirmHandler(), withBodyLimit(mwAuthorizedPost, authBodyLimit)))
+	r.Post("/api/user/password", mw(handlers.ChangePasswordHandler(), withBodyLimit(mwAuthorizedPost, authBodyLimit)))
+	r.Post("/api/user/password/reset", mw(handlers.PasswordResetHandler(), withBodyLimit(mwPublicPost, authBodyLimit)))
+	r.Post("/api/user/password/reset/confirm", mw(handlers.PasswordResetConfirmHandler(), withBodyLimit(mwPublicPost, authBodyLimit)))
+	r.Post("/api/user/orders", mw(handlers.CreateOrderHandler(), withBodyLimit(mwOrdersWritePost, orderBodyLimit)))
+	r.Get("/api/user/orders", mw(handlers.OrderListHandler(), mwOrdersReadGet))
+	r.Get("/api/user/balance", mw(handlers.BalanceHandler(), mwBalanceReadGet))
+	r.Post("/api/user/balance/withdraw", mw(handlers.WithdrawRequestHandler(), mwBalanceWithdrawPost))
+	r.Get("/api/user/withdrawals", mw(handlers.WithdrawListHandler(), mwBalanceReadGet))
+	r.Get("/api/user/balance/adjustments", mw(handlers.AdjustmentListHandler(), mwBalanceReadGet))
+	r.Get("/api/user/statement", mw(handlers.StatementHandler(), mwBalanceReadGet))
+	r.Get("/api/user/statement/export", mw(handlers.StatementExportHandler(), mwBalanceReadGet))
+
+	r.Route("/api/admin", func(r chi.Router) {
+		r.Get("/metrics", mw(handlers.MetricsHandler(), mwAdminGet))
+		r.Get("/users", mw(handlers.AdminSearchUsersHandler(), mwSupportGet))
+		r.Post("/users/unlock", mw(handlers.AdminUnlockUserHandler(), mwSupportPost))
+		r.Get("/users/{id}", mw(handlers.AdminUserHandler(), mwSupportGet))
+		r.Delete("/users/{id}", mw(handlers.AdminDeleteUserHandler(), mwAdminDelete))
+		r.Get("/users/{id}/orders", mw(handlers.AdminUserOrdersHandler(), mwSupportGet))
+		r.Get("/users/{id}/withdrawals", mw(handlers.AdminUserWithdrawalsHandler(), mwSupportGet))
+		r.Post("/users/{id}/block", mw(handlers.AdminBlockUserHandler(), mwAdminPost))
+		r.Post("/users/{id}/unblock", mw(handlers.AdminUnblockUserHandler(), mwAdminPost))
+		r.Post("/users/{id}/role", mw(handlers.AdminSetUserRoleHandler(), mwAdminPost))
+		r.Get("/users/{id}/api-keys", mw(handlers.AdminAPIKeyListHandler(), mwSupportGet))
+		r.Post("/users/{id}/api-keys", mw(handlers.AdminCreateAPIKeyHandler(), mwAdminPost))
+		r.Delete("/users/{id}/api-keys/{keyID}", mw(handlers.AdminRevokeAPIKeyHandler(), mwAdminDelete))
+		r.Get("/users/{id}/adjustments", mw(handlers.AdminUserAdjustmentsHandler(), mwSupportGet))
+		r.Post("/users/{id}/adjustments", mw(handlers.AdminCreateAdjustmentHandler(), mwAdminPost))
+		r.Post("/orders/{number}/requeue", mw(handlers.AdminRequeueOrderHandler(), mwSupportPost))
+		r.Get("/adjustments", mw(handlers.AdminPendingAdjustmentsHandler(), mwAdminGet))
+		r.Post("/adjustments/{id}/approve", mw(handlers.AdminApproveAdjustmentHandler(), mwAdminPost))
+		r.Post("/adjustments/{id}/reject", mw(handlers.AdminRejectAdjustmentHandler(), mwAdminPost))
+	})
 
 	return r
 }
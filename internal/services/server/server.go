@@ -4,13 +4,43 @@ import (
 	"net/http"
 
 	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/compression"
 	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/user"
 )
 
 func Run(env config.AppEnvironment) {
+	authenticator = auth.Chain{user.NewJWTAuthenticator(env)}
+	apiKeyAuthenticator = auth.Chain{authenticator, user.NewAPIKeyAuthenticator()}
 	router := getRoute()
 
-	err := http.ListenAndServe(env.GetRunAddress(), router)
+	maxBodySize = env.GetMaxRequestBodySize()
+	compression.Init(env)
+
+	srv := &http.Server{
+		Addr:              env.GetRunAddress(),
+		Handler:           router,
+		ReadHeaderTimeout: env.GetServerReadHeaderTimeout(),
+		ReadTimeout:       env.GetServerReadTimeout(),
+		WriteTimeout:      env.GetServerWriteTimeout(),
+		IdleTimeout:       env.GetServerIdleTimeout(),
+	}
+
+	var err error
+	if env.GetTLSCertFile() != "" || env.GetTLSKeyFile() != "" {
+		srv.TLSConfig, err = newTLSConfig(env)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Fatal("fail configure tls", args)
+			return
+		}
+
+		err = srv.ListenAndServeTLS("", "")
+	} else {
+		err = srv.ListenAndServe()
+	}
+
 	if err != nil {
 		args := map[string]interface{}{"error": err.Error()}
 		logger.Fatal("fail run server", args)
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/crash"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+)
+
+const certReloadInterval = 30 * time.Second
+
+// requireClientCert is set when a client CA is configured. Admin routes then
+// accept only requests with a verified client certificate.
+var requireClientCert bool
+
+// certReloader keeps the server certificate and client CA pool loaded from
+// disk. Files are reloaded when their modification time changes and on
+// SIGHUP, so renewed certificates are picked up without a restart.
+type certReloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+}
+
+func newTLSConfig(env config.AppEnvironment) (*tls.Config, error) {
+	if env.GetTLSCertFile() == "" || env.GetTLSKeyFile() == "" {
+		return nil, errors.New("both tls certificate and key files are required")
+	}
+
+	reloader := &certReloader{
+		certFile: env.GetTLSCertFile(),
+		keyFile:  env.GetTLSKeyFile(),
+		caFile:   env.GetTLSClientCAFile(),
+	}
+	if err := reloader.load(); err != nil {
+		return nil, err
+	}
+
+	suites, err := cipherSuites(env.GetTLSCipherSuites())
+	if err != nil {
+		return nil, err
+	}
+
+	base := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		CipherSuites: suites,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if env.GetTLSMinVersion() == config.TLSVersion13 {
+		base.MinVersion = tls.VersionTLS13
+	}
+
+	// Client certificates are optional on the handshake, as only admin routes
+	// require them; mwClientCert enforces it there.
+	if reloader.caFile != "" {
+		base.ClientAuth = tls.VerifyClientCertIfGiven
+		requireClientCert = true
+	}
+
+	crash.Go("tls_reloader", reloader.watch)
+
+	cnf := base.Clone()
+	cnf.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		reloader.mu.RLock()
+		defer reloader.mu.RUnlock()
+
+		c := base.Clone()
+		c.Certificates = []tls.Certificate{*reloader.cert}
+		c.ClientCAs = reloader.clientCAs
+		return c, nil
+	}
+
+	return cnf, nil
+}
+
+// load reads all files and swaps them in only when every file is valid, so a
+// half-written renewal keeps the previous certificate in use.
+func (c *certReloader) load() error {
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed load tls key pair: %w", err)
+	}
+
+	var pool *x509.CertPool
+	if c.caFile != "" {
+		pem, err := os.ReadFile(c.caFile)
+		if err != nil {
+			return fmt.Errorf("failed read tls client ca file: %w", err)
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return errors.New("no certificates found in tls client ca file")
+		}
+	}
+
+	modTimes, err := c.fileModTimes()
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.cert = &cert
+	c.clientCAs = pool
+	c.modTimes = modTimes
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *certReloader) watch() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	ticker := time.NewTicker(certReloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-hup:
+			c.reload("sighup")
+		case <-ticker.C:
+			if c.changed() {
+				c.reload("file_changed")
+			}
+		}
+	}
+}
+
+func (c *certReloader) reload(reason string) {
+	if err := c.load(); err != nil {
+		args := map[string]interface{}{"error": err.Error(), "reason": reason}
+		logger.Error("failed reload tls certificates, previous certificates are kept", args)
+		return
+	}
+
+	args := map[string]interface{}{"reason": reason, "cert": c.certFile}
+	logger.Info("tls certificates reloaded", args)
+}
+
+func (c *certReloader) changed() bool {
+	modTimes, err := c.fileModTimes()
+	if err != nil {
+		return false
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for file, modTime := range modTimes {
+		if !modTime.Equal(c.modTimes[file]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *certReloader) fileModTimes() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	for _, file := range []string{c.certFile, c.keyFile, c.caFile} {
+		if file == "" {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
+
+// cipherSuites maps suite names to ids. Only suites Go considers secure are
+// accepted. TLS 1.3 suites are not configurable and always enabled.
+func cipherSuites(value string) ([]uint16, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	var ids []uint16
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure tls cipher suite %s", name)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
+
+// mwClientCert rejects requests without a verified client certificate when
+// mutual TLS is configured.
+func mwClientCert(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requireClientCert && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+			args := map[string]interface{}{"path": r.URL.Path, "ip": r.RemoteAddr}
+			logger.Security("client_certificate_required", args)
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	}
+}
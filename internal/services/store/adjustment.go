@@ -0,0 +1,198 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	AdjustmentTypeCredit = "credit"
+	AdjustmentTypeDebit  = "debit"
+
+	AdjustmentStatusPending  = "pending"
+	AdjustmentStatusApproved = "approved"
+	AdjustmentStatusRejected = "rejected"
+
+	adjustmentColumns = "id,user_id,type,amount,reason_code,comment,status,created_by,decided_by,created_at,decided_at"
+
+	insertAdjustmentSQL         = "INSERT INTO balance_adjustments (user_id, type, amount, reason_code, comment, status, created_by, decided_by, decided_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING " + adjustmentColumns
+	getAdjustmentsByUserIDSQL   = "SELECT " + adjustmentColumns + " FROM balance_adjustments WHERE user_id = $1 ORDER BY created_at"
+	getAdjustmentsByStatusSQL   = "SELECT " + adjustmentColumns + " FROM balance_adjustments WHERE status = $1 ORDER BY created_at"
+	adjustmentAmountByUserIDSQL = "SELECT sum(CASE WHEN type = $2 THEN amount ELSE -amount END) FROM balance_adjustments WHERE user_id = $1 AND status = $3"
+	decideAdjustmentSQL         = "UPDATE balance_adjustments SET status = $1, decided_by = $2, decided_at = NOW() WHERE id = $3 AND status = $4 AND created_by <> $2 AND user_id <> $2 RETURNING " + adjustmentColumns
+	autoApprovedAmountSQL       = "SELECT COALESCE(sum(amount), 0) FROM balance_adjustments WHERE created_by = $1 AND decided_by = $1 AND status = $2 AND created_at > $3"
+	// lockAdjustmentActorSQL serializes adjustments of one actor, so parallel
+	// requests cannot all fit under the auto-approval limit.
+	lockAdjustmentActorSQL = "SELECT pg_advisory_xact_lock($1, $2)"
+	adjustmentLockClass    = 38
+)
+
+// CreateBalanceAdjustment saves a pending adjustment. It is approved on
+// behalf of its creator right away when the amount together with everything
+// the creator had auto-approved since the given time stays within the limit.
+func CreateBalanceAdjustment(adjustment BalanceAdjustment, autoApproveLimit float64, since time.Time) (BalanceAdjustment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return BalanceAdjustment{}, err
+	}
+	defer conn.Close(ctx)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to begin transaction", args)
+		return BalanceAdjustment{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, lockAdjustmentActorSQL, int32(adjustmentLockClass), int32(adjustment.CreatedBy))
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "actorID": adjustment.CreatedBy}
+		logger.Error("failed execute query 'lockAdjustmentActorSQL'", args)
+		return BalanceAdjustment{}, err
+	}
+
+	var approved float64
+	err = tx.QueryRow(ctx, autoApprovedAmountSQL, adjustment.CreatedBy, AdjustmentStatusApproved, since).Scan(&approved)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "actorID": adjustment.CreatedBy}
+		logger.Error("failed execute query 'autoApprovedAmountSQL'", args)
+		return BalanceAdjustment{}, err
+	}
+
+	adjustment.Status = AdjustmentStatusPending
+	adjustment.DecidedBy = nil
+	adjustment.DecidedAt = nil
+	if approved+adjustment.Amount <= autoApproveLimit {
+		now := time.Now()
+		adjustment.Status = AdjustmentStatusApproved
+		adjustment.DecidedBy = &adjustment.CreatedBy
+		adjustment.DecidedAt = &now
+	}
+
+	row := tx.QueryRow(ctx, insertAdjustmentSQL, adjustment.UserID, adjustment.Type, adjustment.Amount,
+		adjustment.ReasonCode, adjustment.Comment, adjustment.Status, adjustment.CreatedBy, adjustment.DecidedBy,
+		adjustment.DecidedAt)
+	created, err := scanAdjustment(row)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": adjustment.UserID}
+		logger.Error("failed execute query 'insertAdjustmentSQL'", args)
+		return BalanceAdjustment{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return BalanceAdjustment{}, err
+	}
+
+	return created, nil
+}
+
+func GetBalanceAdjustmentsByUserID(userID int) ([]BalanceAdjustment, error) {
+	return queryAdjustments("getAdjustmentsByUserIDSQL", getAdjustmentsByUserIDSQL, userID)
+}
+
+func GetBalanceAdjustmentsByStatus(status string) ([]BalanceAdjustment, error) {
+	return queryAdjustments("getAdjustmentsByStatusSQL", getAdjustmentsByStatusSQL, status)
+}
+
+// GetAdjustmentAmountByUserID returns the sum of approved credits minus
+// approved debits of the user.
+func GetAdjustmentAmountByUserID(userID int) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("error connecting to database", args)
+		return 0, err
+	}
+	defer conn.Close(ctx)
+
+	var amount sql.NullFloat64
+	err = conn.QueryRow(ctx, adjustmentAmountByUserIDSQL, userID, AdjustmentTypeCredit, AdjustmentStatusApproved).Scan(&amount)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("error execute query getting adjustment amount", args)
+		return 0, err
+	}
+
+	return amount.Float64, nil
+}
+
+// DecideBalanceAdjustment approves or rejects a pending adjustment. Neither
+// the creator nor the adjusted user can decide on it; pgx.ErrNoRows is
+// returned when the adjustment is not pending or the admin is one of them.
+func DecideBalanceAdjustment(id int, status string, adminID int) (BalanceAdjustment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return BalanceAdjustment{}, err
+	}
+	defer conn.Close(ctx)
+
+	row := conn.QueryRow(ctx, decideAdjustmentSQL, status, adminID, id, AdjustmentStatusPending)
+	adjustment, err := scanAdjustment(row)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "adjustmentID": id}
+		logger.Error("failed execute query 'decideAdjustmentSQL'", args)
+		return BalanceAdjustment{}, err
+	}
+
+	return adjustment, nil
+}
+
+func queryAdjustments(name string, query string, param interface{}) ([]BalanceAdjustment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	rows, err := conn.Query(ctx, query, param)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query '"+name+"'", args)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []BalanceAdjustment
+	for rows.Next() {
+		adjustment, err := scanAdjustment(rows)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("failed scan balance adjustment", args)
+			return nil, err
+		}
+		result = append(result, adjustment)
+	}
+
+	return result, rows.Err()
+}
+
+func scanAdjustment(row pgx.Row) (BalanceAdjustment, error) {
+	var a BalanceAdjustment
+	err := row.Scan(&a.ID, &a.UserID, &a.Type, &a.Amount, &a.ReasonCode, &a.Comment, &a.Status, &a.CreatedBy,
+		&a.DecidedBy, &a.CreatedAt, &a.DecidedAt)
+
+	return a, err
+}
@@ -0,0 +1,137 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	apiKeyColumns = "id,user_id,name,key_prefix,key_hash,scopes,created_by,created_at,expires_at,last_used_at,revoked_at"
+
+	insertAPIKeySQL       = "INSERT INTO api_keys (user_id, name, key_prefix, key_hash, scopes, created_by, expires_at) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING " + apiKeyColumns
+	getAPIKeyByHashSQL    = "SELECT " + apiKeyColumns + " FROM api_keys WHERE key_hash = $1"
+	getAPIKeysByUserIDSQL = "SELECT " + apiKeyColumns + " FROM api_keys WHERE user_id = $1 AND revoked_at IS NULL ORDER BY created_at"
+	touchAPIKeySQL        = "UPDATE api_keys SET last_used_at = NOW() WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')"
+	revokeAPIKeySQL       = "UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL"
+)
+
+func CreateAPIKey(key APIKey) (APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return APIKey{}, err
+	}
+	defer conn.Close(ctx)
+
+	row := conn.QueryRow(ctx, insertAPIKeySQL, key.UserID, key.Name, key.KeyPrefix, key.KeyHash, key.Scopes,
+		key.CreatedBy, key.ExpiresAt)
+	created, err := scanAPIKey(row)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": key.UserID}
+		logger.Error("failed execute query 'insertAPIKeySQL'", args)
+		return APIKey{}, err
+	}
+
+	return created, nil
+}
+
+func GetAPIKeyByHash(hash string) (APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return APIKey{}, err
+	}
+	defer conn.Close(ctx)
+
+	key, err := scanAPIKey(conn.QueryRow(ctx, getAPIKeyByHashSQL, hash))
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query 'getAPIKeyByHashSQL'", args)
+		return APIKey{}, err
+	}
+
+	return key, nil
+}
+
+// GetAPIKeysByUserID returns the keys of the user which have not been revoked.
+func GetAPIKeysByUserID(userID int) ([]APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	rows, err := conn.Query(ctx, getAPIKeysByUserIDSQL, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'getAPIKeysByUserIDSQL'", args)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("failed scan api key", args)
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// TouchAPIKey records the key usage. The time is updated at most once a
+// minute to avoid a write on every request.
+func TouchAPIKey(id int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, touchAPIKeySQL, id)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "keyID": id}
+		logger.Error("failed execute query 'touchAPIKeySQL'", args)
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAPIKey returns false when the user has no such active key.
+func RevokeAPIKey(id int, userID int) (bool, error) {
+	return execUpdate("revokeAPIKeySQL", revokeAPIKeySQL, id, userID)
+}
+
+func scanAPIKey(row pgx.Row) (APIKey, error) {
+	var k APIKey
+	err := row.Scan(&k.ID, &k.UserID, &k.Name, &k.KeyPrefix, &k.KeyHash, &k.Scopes, &k.CreatedBy, &k.CreatedAt,
+		&k.ExpiresAt, &k.LastUsedAt, &k.RevokedAt)
+
+	return k, err
+}
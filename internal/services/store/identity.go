@@ -0,0 +1,82 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	getUserByIdentitySQL   = "SELECT " + userColumns + " from users where id = (SELECT user_id FROM user_identities WHERE issuer = $1 AND subject = $2)"
+	insertUserReturningSQL = "INSERT INTO users (login, login_normalized, password, email) VALUES ($1, $2, $3, NULLIF($4, '')) RETURNING " + userColumns
+	insertUserIdentitySQL  = "INSERT INTO user_identities (user_id, issuer, subject) VALUES ($1, $2, $3)"
+)
+
+// GetUserByIdentity returns the user linked to the subject of the external
+// identity provider. It returns pgx.ErrNoRows when no user is linked.
+func GetUserByIdentity(issuer string, subject string) (User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return User{}, err
+	}
+	defer conn.Close(ctx)
+
+	user, err := scanUser(conn.QueryRow(ctx, getUserByIdentitySQL, issuer, subject))
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "issuer": issuer, "subject": subject}
+		logger.Error("failed execute query 'getUserByIdentitySQL'", args)
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+// CreateIdentityUser creates a user and links it to the subject of the
+// external identity provider in one transaction.
+func CreateIdentityUser(login string, normalized string, password string, email string, issuer string, subject string) (User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return User{}, err
+	}
+	defer conn.Close(ctx)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to begin transaction", args)
+		return User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	user, err := scanUser(tx.QueryRow(ctx, insertUserReturningSQL, login, normalized, password, email))
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "login": login}
+		logger.Error("failed execute query 'insertUserReturningSQL'", args)
+		return User{}, err
+	}
+
+	_, err = tx.Exec(ctx, insertUserIdentitySQL, user.ID, issuer, subject)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": user.ID, "issuer": issuer}
+		logger.Error("failed execute query 'insertUserIdentitySQL'", args)
+		return User{}, err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return User{}, err
+	}
+
+	return user, nil
+}
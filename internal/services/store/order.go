@@ -20,7 +20,7 @@ const (
 	insertOrderSQL           = "INSERT INTO orders (number, user_id, status) VALUES ($1, $2, $3)"
 	getOrdersByUserIDSQL     = "SELECT id,user_id,number,status,accrual,uploaded_at FROM orders WHERE user_id = $1"
 	accrualAmountByUserIDSQL = "SELECT sum(accrual) FROM orders WHERE user_id = $1 and status = $2"
-	updateOrderSQL           = "UPDATE orders SET status = $1, accrual = $2 WHERE id = $3"
+	updateOrderSQL           = "UPDATE orders SET status = $1, accrual = $2, updated_at = NOW() WHERE id = $3"
 )
 
 func GetOrderByNumber(number int) (Order, error) {
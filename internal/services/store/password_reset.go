@@ -0,0 +1,109 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	insertPasswordResetSQL     = "INSERT INTO password_resets (user_id, token_hash, expires_at) VALUES ($1, $2, $3)"
+	expireUserPasswordResetSQL = "UPDATE password_resets SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL"
+	getPasswordResetByHashSQL  = "SELECT id,user_id,token_hash,expires_at,created_at,used_at FROM password_resets WHERE token_hash = $1"
+	usePasswordResetSQL        = "UPDATE password_resets SET used_at = NOW() WHERE id = $1 AND used_at IS NULL AND expires_at > NOW()"
+)
+
+// CreatePasswordReset saves a new reset token and invalidates the previous
+// unused tokens of the user, so only the latest sent token works.
+func CreatePasswordReset(userID int, tokenHash string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to begin transaction", args)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, expireUserPasswordResetSQL, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'expireUserPasswordResetSQL'", args)
+		return err
+	}
+
+	res, err := tx.Exec(ctx, insertPasswordResetSQL, userID, tokenHash, expiresAt)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'insertPasswordResetSQL'", args)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		logger.Error("failed to insert password reset", nil)
+		return errors.New("failed to insert password reset")
+	}
+
+	return tx.Commit(ctx)
+}
+
+func GetPasswordResetByHash(tokenHash string) (PasswordReset, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return PasswordReset{}, err
+	}
+	defer conn.Close(ctx)
+
+	var reset PasswordReset
+	err = conn.QueryRow(ctx, getPasswordResetByHashSQL, tokenHash).Scan(&reset.ID, &reset.UserID, &reset.TokenHash,
+		&reset.ExpiresAt, &reset.CreatedAt, &reset.UsedAt)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query 'getPasswordResetByHashSQL'", args)
+		return PasswordReset{}, err
+	}
+
+	return reset, nil
+}
+
+// UsePasswordReset marks the reset token as used. It returns false when the
+// token is already used or expired.
+func UsePasswordReset(id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return false, err
+	}
+	defer conn.Close(ctx)
+
+	res, err := conn.Exec(ctx, usePasswordResetSQL, id)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query 'usePasswordResetSQL'", args)
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
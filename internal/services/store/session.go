@@ -0,0 +1,221 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	insertSessionSQL   = "INSERT INTO sessions (id, user_id, ip, user_agent) VALUES ($1, $2, $3, $4)"
+	getUserSessionsSQL = "SELECT id,user_id,ip,user_agent,created_at,last_seen,revoked_at FROM sessions WHERE user_id = $1 AND revoked_at IS NULL ORDER BY last_seen DESC"
+	getSessionSQL      = "SELECT id,user_id,ip,user_agent,created_at,last_seen,revoked_at FROM sessions WHERE id = $1 AND user_id = $2"
+	// touchSessionSQL writes last_seen at most once a minute but always
+	// reports whether the session is active.
+	touchSessionSQL = "WITH active AS (SELECT id FROM sessions WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL), " +
+		"touched AS (UPDATE sessions SET last_seen = NOW() WHERE id IN (SELECT id FROM active) AND last_seen < NOW() - INTERVAL '1 minute') " +
+		"SELECT count(id) FROM active"
+	revokeSessionSQL           = "UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL"
+	revokeUserSessionsSQL      = "UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL"
+	revokeSessionRefreshSQL    = "UPDATE refresh_tokens SET revoked_at = NOW() WHERE session_id = $1 AND revoked_at IS NULL"
+	revokeSessionsRefreshesSQL = "UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND session_id <> $2 AND revoked_at IS NULL"
+)
+
+func CreateSession(session Session) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	res, err := conn.Exec(ctx, insertSessionSQL, session.ID, session.UserID, session.IP, session.UserAgent)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": session.UserID}
+		logger.Error("failed execute query 'insertSessionSQL'", args)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		logger.Error("failed to insert session", nil)
+		return errors.New("failed to insert session")
+	}
+
+	return nil
+}
+
+// GetUserSessions returns active sessions of the user, most recently used first.
+func GetUserSessions(userID int) ([]Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	rows, err := conn.Query(ctx, getUserSessionsSQL, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'getUserSessionsSQL'", args)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		var s Session
+		err = rows.Scan(&s.ID, &s.UserID, &s.IP, &s.UserAgent, &s.CreatedAt, &s.LastSeen, &s.RevokedAt)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("failed scan user session", args)
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	return sessions, rows.Err()
+}
+
+func GetSession(sessionID string, userID int) (Session, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return Session{}, err
+	}
+	defer conn.Close(ctx)
+
+	var s Session
+	err = conn.QueryRow(ctx, getSessionSQL, sessionID, userID).Scan(&s.ID, &s.UserID, &s.IP, &s.UserAgent,
+		&s.CreatedAt, &s.LastSeen, &s.RevokedAt)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "sessionID": sessionID}
+		logger.Error("failed execute query 'getSessionSQL'", args)
+		return Session{}, err
+	}
+
+	return s, nil
+}
+
+// TouchSession updates the last activity time of the session, at most once
+// a minute. It returns false when the session does not exist or has been
+// revoked.
+func TouchSession(sessionID string, userID int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return false, err
+	}
+	defer conn.Close(ctx)
+
+	var count int
+	err = conn.QueryRow(ctx, touchSessionSQL, sessionID, userID).Scan(&count)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "sessionID": sessionID}
+		logger.Error("failed execute query 'touchSessionSQL'", args)
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// RevokeSession revokes the user session together with its refresh tokens.
+// It returns false when the user has no such active session.
+func RevokeSession(userID int, sessionID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return false, err
+	}
+	defer conn.Close(ctx)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to begin transaction", args)
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(ctx, revokeSessionSQL, sessionID, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "sessionID": sessionID}
+		logger.Error("failed execute query 'revokeSessionSQL'", args)
+		return false, err
+	}
+
+	if res.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	_, err = tx.Exec(ctx, revokeSessionRefreshSQL, sessionID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "sessionID": sessionID}
+		logger.Error("failed execute query 'revokeSessionRefreshSQL'", args)
+		return false, err
+	}
+
+	return true, tx.Commit(ctx)
+}
+
+// RevokeUserSessions revokes all sessions of the user except the given one,
+// together with their refresh tokens. Pass an empty exceptSessionID to revoke
+// every session. It returns the number of revoked sessions.
+func RevokeUserSessions(userID int, exceptSessionID string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return 0, err
+	}
+	defer conn.Close(ctx)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to begin transaction", args)
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(ctx, revokeUserSessionsSQL, userID, exceptSessionID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'revokeUserSessionsSQL'", args)
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, revokeSessionsRefreshesSQL, userID, exceptSessionID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'revokeSessionsRefreshesSQL'", args)
+		return 0, err
+	}
+
+	return int(res.RowsAffected()), tx.Commit(ctx)
+}
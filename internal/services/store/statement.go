@@ -0,0 +1,184 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	StatementEntryAccrual    = "accrual"
+	StatementEntryWithdrawal = "withdrawal"
+	StatementEntryAdjustment = "adjustment"
+
+	// statementLedgerSQL merges everything that moves the balance of user $1
+	// into one signed ledger. The running balance is calculated over the whole
+	// history before the period filter is applied, so the first entry of a
+	// page still shows the real balance.
+	statementLedgerSQL = "WITH entries AS (" +
+		"SELECT '" + StatementEntryAccrual + "' AS type, id, number::text AS reference, accrual AS amount, updated_at AS occurred_at " +
+		"FROM orders WHERE user_id = $1 AND status = $2 " +
+		"UNION ALL " +
+		"SELECT '" + StatementEntryWithdrawal + "', id, order_num::text, -sum, COALESCE(processed_at, 'epoch'::timestamp) " +
+		"FROM withdraw WHERE user_id = $1 " +
+		"UNION ALL " +
+		"SELECT '" + StatementEntryAdjustment + "', id, reason_code, CASE WHEN type = $3 THEN amount ELSE -amount END, COALESCE(decided_at, created_at) " +
+		"FROM balance_adjustments WHERE user_id = $1 AND status = $4" +
+		"), ledger AS (" +
+		"SELECT type, id, reference, amount, occurred_at, " +
+		"sum(amount) OVER (ORDER BY occurred_at, type, id ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS balance " +
+		"FROM entries" +
+		") "
+
+	getStatementSQL = statementLedgerSQL +
+		"SELECT type, reference, amount, balance, occurred_at FROM ledger " +
+		"WHERE ($5::timestamp IS NULL OR occurred_at >= $5) AND ($6::timestamp IS NULL OR occurred_at < $6) " +
+		"ORDER BY occurred_at, type, id LIMIT $7 OFFSET $8"
+
+	countStatementSQL = statementLedgerSQL +
+		"SELECT count(*) FROM ledger " +
+		"WHERE ($5::timestamp IS NULL OR occurred_at >= $5) AND ($6::timestamp IS NULL OR occurred_at < $6)"
+
+	openingBalanceSQL = statementLedgerSQL +
+		"SELECT COALESCE(sum(amount), 0) FROM ledger WHERE occurred_at < $5"
+
+	// StatementExportTimeout bounds a streamed export, which lasts as long as
+	// the client needs to receive it.
+	StatementExportTimeout = 5 * time.Minute
+)
+
+type StatementEntry struct {
+	Type       string    `db:"type"`
+	Reference  string    `db:"reference"`
+	Amount     float64   `db:"amount"`
+	Balance    float64   `db:"balance"`
+	OccurredAt time.Time `db:"occurred_at"`
+}
+
+// StatementFilter limits a statement to the period [From, To). A nil bound
+// leaves that side of the period open.
+type StatementFilter struct {
+	From   *time.Time
+	To     *time.Time
+	Limit  int
+	Offset int
+}
+
+// GetStatement returns the balance movements of the user in chronological
+// order together with the balance after each of them.
+func GetStatement(userID int, filter StatementFilter) ([]StatementEntry, error) {
+	var entries []StatementEntry
+	err := eachStatementEntry(userID, filter, 5*time.Second, func(entry StatementEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// EachStatementEntry passes the statement entries to fn one by one as they
+// are read from the database, so a whole period can be exported without
+// holding it in memory. A zero limit returns all entries of the period.
+func EachStatementEntry(userID int, filter StatementFilter, fn func(StatementEntry) error) error {
+	return eachStatementEntry(userID, filter, StatementExportTimeout, fn)
+}
+
+// CountStatementEntries returns the number of statement entries of the user
+// in the period of the filter. Limit and Offset are ignored.
+func CountStatementEntries(userID int, filter StatementFilter) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return 0, err
+	}
+	defer conn.Close(ctx)
+
+	var count int
+	err = conn.QueryRow(ctx, countStatementSQL, userID, OrderStatusProcessed, AdjustmentTypeCredit,
+		AdjustmentStatusApproved, filter.From, filter.To).Scan(&count)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'countStatementSQL'", args)
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// GetOpeningBalance returns the balance of the user right before the moment.
+func GetOpeningBalance(userID int, at time.Time) (float64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return 0, err
+	}
+	defer conn.Close(ctx)
+
+	var balance float64
+	err = conn.QueryRow(ctx, openingBalanceSQL, userID, OrderStatusProcessed, AdjustmentTypeCredit,
+		AdjustmentStatusApproved, at).Scan(&balance)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'openingBalanceSQL'", args)
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func eachStatementEntry(userID int, filter StatementFilter, timeout time.Duration, fn func(StatementEntry) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	// NULL turns LIMIT off.
+	var limit interface{}
+	if filter.Limit > 0 {
+		limit = filter.Limit
+	}
+
+	rows, err := conn.Query(ctx, getStatementSQL, userID, OrderStatusProcessed, AdjustmentTypeCredit,
+		AdjustmentStatusApproved, filter.From, filter.To, limit, filter.Offset)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'getStatementSQL'", args)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry StatementEntry
+		err := rows.Scan(&entry.Type, &entry.Reference, &entry.Amount, &entry.Balance, &entry.OccurredAt)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("failed scan statement entry", args)
+			return err
+		}
+
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
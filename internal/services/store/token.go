@@ -0,0 +1,192 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	insertRefreshTokenSQL        = "INSERT INTO refresh_tokens (user_id, session_id, token_hash, expires_at) VALUES ($1, $2, $3, $4)"
+	getRefreshTokenByHashSQL     = "SELECT id,user_id,session_id,token_hash,expires_at,created_at,used_at,revoked_at FROM refresh_tokens WHERE token_hash = $1"
+	markRefreshTokenUsedSQL      = "UPDATE refresh_tokens SET used_at = NOW() WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL"
+	revokeRefreshTokenSessionSQL = "UPDATE refresh_tokens SET revoked_at = NOW() WHERE session_id = $1 AND revoked_at IS NULL"
+	revokeUserRefreshTokensSQL   = "UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL"
+	insertRevokedTokenSQL        = "INSERT INTO revoked_tokens (jti, expires_at) VALUES ($1, $2) ON CONFLICT (jti) DO NOTHING"
+	deleteExpiredRevokedSQL      = "DELETE FROM revoked_tokens WHERE expires_at < NOW()"
+	hasRevokedTokenSQL           = "SELECT count(jti) FROM revoked_tokens WHERE jti = $1"
+)
+
+func CreateRefreshToken(token RefreshToken) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	res, err := conn.Exec(ctx, insertRefreshTokenSQL, token.UserID, token.SessionID, token.TokenHash, token.ExpiresAt)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query 'insertRefreshTokenSQL'", args)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		logger.Error("failed to insert refresh token", nil)
+		return errors.New("failed to insert refresh token")
+	}
+
+	return nil
+}
+
+func GetRefreshTokenByHash(hash string) (RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return RefreshToken{}, err
+	}
+	defer conn.Close(ctx)
+
+	var token RefreshToken
+	err = conn.QueryRow(ctx, getRefreshTokenByHashSQL, hash).Scan(&token.ID, &token.UserID, &token.SessionID,
+		&token.TokenHash, &token.ExpiresAt, &token.CreatedAt, &token.UsedAt, &token.RevokedAt)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query 'getRefreshTokenByHashSQL'", args)
+		return RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+// MarkRefreshTokenUsed flags the token as rotated. It returns false when the
+// token has already been used or revoked, so concurrent refreshes of the same
+// token are detected as reuse.
+func MarkRefreshTokenUsed(id int) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return false, err
+	}
+	defer conn.Close(ctx)
+
+	res, err := conn.Exec(ctx, markRefreshTokenUsedSQL, id)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query 'markRefreshTokenUsedSQL'", args)
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+func RevokeRefreshTokenSession(sessionID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, revokeRefreshTokenSessionSQL, sessionID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "sessionID": sessionID}
+		logger.Error("failed execute query 'revokeRefreshTokenSessionSQL'", args)
+		return err
+	}
+
+	return nil
+}
+
+func RevokeUserRefreshTokens(userID int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, revokeUserRefreshTokensSQL, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'revokeUserRefreshTokensSQL'", args)
+		return err
+	}
+
+	return nil
+}
+
+func RevokeToken(jti string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	_, err = conn.Exec(ctx, deleteExpiredRevokedSQL)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query 'deleteExpiredRevokedSQL'", args)
+	}
+
+	_, err = conn.Exec(ctx, insertRevokedTokenSQL, jti, expiresAt)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "jti": jti}
+		logger.Error("failed execute query 'insertRevokedTokenSQL'", args)
+		return err
+	}
+
+	return nil
+}
+
+func IsTokenRevoked(jti string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return false, err
+	}
+	defer conn.Close(ctx)
+
+	var count int
+	err = conn.QueryRow(ctx, hasRevokedTokenSQL, jti).Scan(&count)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query 'hasRevokedTokenSQL'", args)
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,146 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	setTOTPSecretSQL       = "UPDATE users SET totp_secret = $1, totp_enabled = FALSE WHERE id = $2 AND totp_enabled = FALSE"
+	enableTOTPSQL          = "UPDATE users SET totp_enabled = TRUE WHERE id = $1 AND totp_secret IS NOT NULL"
+	useTOTPStepSQL         = "UPDATE users SET totp_last_step = $1 WHERE id = $2 AND totp_last_step < $1"
+	deleteRecoveryCodesSQL = "DELETE FROM recovery_codes WHERE user_id = $1"
+	insertRecoveryCodeSQL  = "INSERT INTO recovery_codes (user_id, code_hash) VALUES ($1, $2)"
+	useRecoveryCodeSQL     = "UPDATE recovery_codes SET used_at = NOW() WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL"
+)
+
+// SetUserTOTPSecret saves a pending TOTP secret. It is not used for login
+// until EnableUserTOTP is called.
+func SetUserTOTPSecret(userID int, secret string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	res, err := conn.Exec(ctx, setTOTPSecretSQL, secret, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'setTOTPSecretSQL'", args)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("failed to set totp secret")
+	}
+
+	return nil
+}
+
+// EnableUserTOTP turns two-factor authentication on and replaces the
+// recovery codes of the user.
+func EnableUserTOTP(userID int, recoveryCodeHashes []string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to begin transaction", args)
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	res, err := tx.Exec(ctx, enableTOTPSQL, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'enableTOTPSQL'", args)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		return errors.New("failed to enable totp")
+	}
+
+	_, err = tx.Exec(ctx, deleteRecoveryCodesSQL, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'deleteRecoveryCodesSQL'", args)
+		return err
+	}
+
+	for _, hash := range recoveryCodeHashes {
+		_, err = tx.Exec(ctx, insertRecoveryCodeSQL, userID, hash)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("failed execute query 'insertRecoveryCodeSQL'", args)
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// UseUserTOTPStep remembers the time step of an accepted code. It returns
+// false when the step (or a later one) was already used, so a code cannot be
+// replayed.
+func UseUserTOTPStep(userID int, step int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return false, err
+	}
+	defer conn.Close(ctx)
+
+	res, err := conn.Exec(ctx, useTOTPStepSQL, step, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'useTOTPStepSQL'", args)
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+func UseRecoveryCode(userID int, codeHash string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return false, err
+	}
+	defer conn.Close(ctx)
+
+	res, err := conn.Exec(ctx, useRecoveryCodeSQL, userID, codeHash)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'useRecoveryCodeSQL'", args)
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
@@ -15,15 +15,35 @@ import (
 )
 
 var ErrorWithdrawNotUnique = errors.New("withdraw order number already exists")
+var ErrorLoginNotUnique = errors.New("login already exists")
 
 type Store struct {
 	connectString string
 }
 
 type User struct {
-	ID       int    `db:"id"`
-	Login    string `db:"login"`
-	Password string `db:"password"`
+	ID           int    `db:"id"`
+	Login        string `db:"login"`
+	Password     string `db:"password"`
+	TokenVersion int    `db:"token_version"`
+	Email        string `db:"email"`
+	TOTPSecret   string `db:"totp_secret"`
+	TOTPEnabled  bool   `db:"totp_enabled"`
+	TOTPLastStep int64  `db:"totp_last_step"`
+	Role         string `db:"role"`
+	Status       string `db:"status"`
+	// LoginConflict is set for accounts whose login clashes with an older
+	// one when case is ignored.
+	LoginConflict bool `db:"login_conflict"`
+}
+
+type PasswordReset struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UsedAt    *time.Time `db:"used_at"`
 }
 
 type Order struct {
@@ -45,6 +65,55 @@ type Withdraw struct {
 
 type WithdrawList []Withdraw
 
+type BalanceAdjustment struct {
+	ID         int        `db:"id"`
+	UserID     int        `db:"user_id"`
+	Type       string     `db:"type"`
+	Amount     float64    `db:"amount"`
+	ReasonCode string     `db:"reason_code"`
+	Comment    string     `db:"comment"`
+	Status     string     `db:"status"`
+	CreatedBy  int        `db:"created_by"`
+	DecidedBy  *int       `db:"decided_by"`
+	CreatedAt  time.Time  `db:"created_at"`
+	DecidedAt  *time.Time `db:"decided_at"`
+}
+
+type RefreshToken struct {
+	ID        int        `db:"id"`
+	UserID    int        `db:"user_id"`
+	SessionID string     `db:"session_id"`
+	TokenHash string     `db:"token_hash"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+type Session struct {
+	ID        string     `db:"id"`
+	UserID    int        `db:"user_id"`
+	IP        string     `db:"ip"`
+	UserAgent string     `db:"user_agent"`
+	CreatedAt time.Time  `db:"created_at"`
+	LastSeen  time.Time  `db:"last_seen"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+type APIKey struct {
+	ID         int        `db:"id"`
+	UserID     int        `db:"user_id"`
+	Name       string     `db:"name"`
+	KeyPrefix  string     `db:"key_prefix"`
+	KeyHash    string     `db:"key_hash"`
+	Scopes     []string   `db:"scopes"`
+	CreatedBy  int        `db:"created_by"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at"`
+	RevokedAt  *time.Time `db:"revoked_at"`
+}
+
 func (s *Store) SetConnectString(str string) error {
 	if str == "" {
 		return errors.New("store connect string is empty")
@@ -3,18 +3,43 @@ package store
 import (
 	"context"
 	"errors"
+	"strings"
 	"time"
 
 	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/jackc/pgerrcode"
 	"github.com/jackc/pgx/v5"
 )
 
 const (
-	hasUserByLoginSQL = "SELECT count(id) FROM users where login = $1"
-	insertUserSQL     = "INSERT INTO users (login, password) VALUES ($1, $2)"
-	getUserByLoginSQL = "SELECT id,login,password from users where login = $1"
+	UserStatusActive  = "active"
+	UserStatusBlocked = "blocked"
+	UserStatusDeleted = "deleted"
+
+	userColumns = "id,login,password,token_version,COALESCE(email, ''),COALESCE(totp_secret, ''),totp_enabled,totp_last_step,role,status,login_conflict"
+
+	hasUserByLoginSQL = "SELECT count(id) FROM users where login_normalized = $1"
+	insertUserSQL     = "INSERT INTO users (login, login_normalized, password, email) VALUES ($1, $2, $3, NULLIF($4, ''))"
+	// Accounts flagged with a login conflict have no normalized login and are
+	// found by their exact login, which takes precedence.
+	getUserByLoginSQL = "SELECT " + userColumns + " from users where login_normalized = $1 OR (login_conflict AND login = $2) " +
+		"ORDER BY login = $2 DESC LIMIT 1"
+	getUserByIDSQL         = "SELECT " + userColumns + " from users where id = $1"
+	getUserTokenVersionSQL = "SELECT token_version from users where id = $1"
+	updatePasswordSQL      = "UPDATE users SET password = $1 WHERE id = $2"
+	changePasswordSQL      = "UPDATE users SET password = $1, token_version = token_version + 1 WHERE id = $2 RETURNING token_version"
+	searchUsersSQL         = "SELECT " + userColumns + " from users where $1 = '' OR lower(login) LIKE $1 OR lower(email) LIKE $1 ORDER BY id LIMIT $2 OFFSET $3"
+	setUserRoleSQL         = "UPDATE users SET role = $1, token_version = token_version + 1 WHERE id = $2 AND role <> $1 RETURNING token_version"
+	setUserStatusSQL       = "UPDATE users SET status = $1, status_changed_at = NOW() WHERE id = $2 AND status <> $1"
+	getUserStatusSQL       = "SELECT status from users where id = $1"
+	// A login with more bytes than characters is not plain ASCII.
+	getNonASCIILoginsSQL  = "SELECT id, login FROM users WHERE octet_length(login) <> char_length(login) AND NOT login_conflict"
+	setNormalizedLoginSQL = "UPDATE users SET login_normalized = $1 WHERE id = $2 AND NOT login_conflict AND login_normalized <> $1"
+	flagLoginConflictSQL  = "UPDATE users SET login_normalized = NULL, login_conflict = TRUE WHERE id = $1 AND NOT login_conflict"
 )
 
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func HasUserByLogin(login string) (bool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -38,7 +63,10 @@ func HasUserByLogin(login string) (bool, error) {
 	return count > 0, nil
 }
 
-func CreateUser(login string, password string) error {
+// CreateUser stores the login as it is displayed together with its
+// normalized form, which is unique. It returns ErrorLoginNotUnique when the
+// normalized login is taken, for example by a concurrent registration.
+func CreateUser(login string, normalized string, password string, email string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -50,8 +78,14 @@ func CreateUser(login string, password string) error {
 	}
 	defer conn.Close(ctx)
 
-	res, err := conn.Exec(ctx, insertUserSQL, login, password)
+	res, err := conn.Exec(ctx, insertUserSQL, login, normalized, password, email)
 	if err != nil {
+		if strings.Contains(err.Error(), pgerrcode.UniqueViolation) {
+			args := map[string]interface{}{"login": normalized}
+			logger.Info("failed insert user: login already exists", args)
+			return ErrorLoginNotUnique
+		}
+
 		args := map[string]interface{}{"error": err.Error()}
 		logger.Error("failed execute query 'insertUserSQL'", args)
 		return err
@@ -66,7 +100,9 @@ func CreateUser(login string, password string) error {
 	return nil
 }
 
-func GetUserByLogin(login string) (User, error) {
+// GetUserByLogin finds the user by the normalized login, or by the exact
+// login for accounts flagged with a login conflict.
+func GetUserByLogin(normalized string, login string) (User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -78,13 +114,300 @@ func GetUserByLogin(login string) (User, error) {
 	}
 	defer conn.Close(ctx)
 
-	var user User
-	err = conn.QueryRow(ctx, getUserByLoginSQL, login).Scan(&user.ID, &user.Login, &user.Password)
+	user, err := scanUser(conn.QueryRow(ctx, getUserByLoginSQL, normalized, login))
 	if err != nil {
-		args := map[string]interface{}{"error": err.Error(), "login": login}
+		args := map[string]interface{}{"error": err.Error(), "login": normalized}
 		logger.Error("failed execute query 'getUserByLoginSQL'", args)
 		return User{}, err
 	}
 
 	return user, nil
 }
+
+func GetUserByID(userID int) (User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return User{}, err
+	}
+	defer conn.Close(ctx)
+
+	user, err := scanUser(conn.QueryRow(ctx, getUserByIDSQL, userID))
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'getUserByIDSQL'", args)
+		return User{}, err
+	}
+
+	return user, nil
+}
+
+func UpdateUserPassword(userID int, password string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return err
+	}
+	defer conn.Close(ctx)
+
+	res, err := conn.Exec(ctx, updatePasswordSQL, password, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'updatePasswordSQL'", args)
+		return err
+	}
+
+	if res.RowsAffected() == 0 {
+		args := map[string]interface{}{"userID": userID}
+		logger.Error("failed execute query 'updatePasswordSQL'", args)
+		return errors.New("failed to update user password")
+	}
+
+	return nil
+}
+
+// ChangeUserPassword saves the new password hash and increments the token
+// version, so all previously issued access tokens stop being accepted. It
+// returns the new token version.
+func ChangeUserPassword(userID int, password string) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return 0, err
+	}
+	defer conn.Close(ctx)
+
+	var version int
+	err = conn.QueryRow(ctx, changePasswordSQL, password, userID).Scan(&version)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'changePasswordSQL'", args)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, errors.New("failed to change user password")
+		}
+
+		return 0, err
+	}
+
+	return version, nil
+}
+
+func GetUserTokenVersion(userID int) (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return 0, err
+	}
+	defer conn.Close(ctx)
+
+	var version int
+	err = conn.QueryRow(ctx, getUserTokenVersionSQL, userID).Scan(&version)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'getUserTokenVersionSQL'", args)
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// SearchUsers returns users whose login or email contains the query. An empty
+// query lists all users.
+func SearchUsers(query string, limit int, offset int) ([]User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	pattern := ""
+	if query != "" {
+		pattern = "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+	}
+
+	rows, err := conn.Query(ctx, searchUsersSQL, pattern, limit, offset)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query 'searchUsersSQL'", args)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("failed scan user", args)
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// SetUserRole changes the user role and increments the token version, so
+// access tokens carrying the old role stop being accepted. It returns the new
+// token version and false when the user does not exist or already has the
+// role.
+func SetUserRole(userID int, role string) (int, bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return 0, false, err
+	}
+	defer conn.Close(ctx)
+
+	var version int
+	err = conn.QueryRow(ctx, setUserRoleSQL, role, userID).Scan(&version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'setUserRoleSQL'", args)
+		return 0, false, err
+	}
+
+	return version, true, nil
+}
+
+// SetUserStatus changes the account status. It returns false when the user
+// does not exist or already has the status.
+func SetUserStatus(userID int, status string) (bool, error) {
+	return execUpdate("setUserStatusSQL", setUserStatusSQL, status, userID)
+}
+
+func GetUserStatus(userID int) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return "", err
+	}
+	defer conn.Close(ctx)
+
+	var status string
+	err = conn.QueryRow(ctx, getUserStatusSQL, userID).Scan(&status)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed execute query 'getUserStatusSQL'", args)
+		return "", err
+	}
+
+	return status, nil
+}
+
+// GetNonASCIILogins returns the id and login of users whose login contains
+// characters beyond ASCII.
+func GetNonASCIILogins() ([]User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return nil, err
+	}
+	defer conn.Close(ctx)
+
+	rows, err := conn.Query(ctx, getNonASCIILoginsSQL)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query 'getNonASCIILoginsSQL'", args)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var u User
+		if err := rows.Scan(&u.ID, &u.Login); err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("failed scan user login", args)
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+// SetNormalizedLogin stores the normalized form of the user login. It returns
+// false when the user already has it and ErrorLoginNotUnique when another
+// account has it.
+func SetNormalizedLogin(userID int, normalized string) (bool, error) {
+	updated, err := execUpdate("setNormalizedLoginSQL", setNormalizedLoginSQL, normalized, userID)
+	if err != nil && strings.Contains(err.Error(), pgerrcode.UniqueViolation) {
+		return false, ErrorLoginNotUnique
+	}
+
+	return updated, err
+}
+
+// FlagLoginConflict marks the user whose normalized login is taken by another
+// account, so support can agree a new login with the owner.
+func FlagLoginConflict(userID int) (bool, error) {
+	return execUpdate("flagLoginConflictSQL", flagLoginConflictSQL, userID)
+}
+
+func execUpdate(name string, query string, params ...interface{}) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return false, err
+	}
+	defer conn.Close(ctx)
+
+	res, err := conn.Exec(ctx, query, params...)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed execute query '"+name+"'", args)
+		return false, err
+	}
+
+	return res.RowsAffected() > 0, nil
+}
+
+func scanUser(row pgx.Row) (User, error) {
+	var user User
+	err := row.Scan(&user.ID, &user.Login, &user.Password, &user.TokenVersion, &user.Email, &user.TOTPSecret,
+		&user.TOTPEnabled, &user.TOTPLastStep, &user.Role, &user.Status, &user.LoginConflict)
+
+	return user, err
+}
@@ -0,0 +1,76 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/jackc/pgx/v5"
+)
+
+// Versions are the row count and the latest change time of a user's rows.
+// Rows are never deleted, so any change alters at least one of them.
+const (
+	ordersVersionSQL      = "SELECT count(id), COALESCE(max(updated_at), 'epoch'::timestamp) FROM orders WHERE user_id = $1"
+	withdrawalsVersionSQL = "SELECT count(id), COALESCE(max(processed_at), 'epoch'::timestamp) FROM withdraw WHERE user_id = $1"
+	balanceVersionSQL     = "SELECT " +
+		"(SELECT count(id) FROM orders WHERE user_id = $1), " +
+		"(SELECT COALESCE(max(updated_at), 'epoch'::timestamp) FROM orders WHERE user_id = $1), " +
+		"(SELECT count(id) FROM withdraw WHERE user_id = $1), " +
+		"(SELECT COALESCE(max(processed_at), 'epoch'::timestamp) FROM withdraw WHERE user_id = $1), " +
+		"(SELECT count(id) FROM balance_adjustments WHERE user_id = $1), " +
+		"(SELECT COALESCE(max(COALESCE(decided_at, created_at)), 'epoch'::timestamp) FROM balance_adjustments WHERE user_id = $1)"
+)
+
+// GetOrdersVersion returns a value which changes whenever an order of the
+// user is added or updated.
+func GetOrdersVersion(userID int) (string, error) {
+	return queryVersion("ordersVersionSQL", ordersVersionSQL, userID, 1)
+}
+
+// GetWithdrawalsVersion returns a value which changes whenever the user
+// withdraws.
+func GetWithdrawalsVersion(userID int) (string, error) {
+	return queryVersion("withdrawalsVersionSQL", withdrawalsVersionSQL, userID, 1)
+}
+
+// GetBalanceVersion returns a value which changes with any order, withdrawal
+// or balance adjustment of the user.
+func GetBalanceVersion(userID int) (string, error) {
+	return queryVersion("balanceVersionSQL", balanceVersionSQL, userID, 3)
+}
+
+func queryVersion(name string, query string, userID int, parts int) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := pgx.Connect(ctx, store.connectString)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed to connect to database", args)
+		return "", err
+	}
+	defer conn.Close(ctx)
+
+	counts := make([]int, parts)
+	times := make([]time.Time, parts)
+	dest := make([]interface{}, 0, parts*2)
+	for i := 0; i < parts; i++ {
+		dest = append(dest, &counts[i], &times[i])
+	}
+
+	err = conn.QueryRow(ctx, query, userID).Scan(dest...)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error(fmt.Sprintf("failed execute query '%s'", name), args)
+		return "", err
+	}
+
+	var version string
+	for i := 0; i < parts; i++ {
+		version += fmt.Sprintf("%d.%d;", counts[i], times[i].UnixNano())
+	}
+
+	return version, nil
+}
@@ -0,0 +1,170 @@
+package user
+
+import (
+	"errors"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxSearchLimit = 100
+
+// SearchAccounts finds users by a part of their login or email for support staff.
+func SearchAccounts(query string, limit int, offset int) ([]Account, error) {
+	if limit <= 0 || limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	users, err := store.SearchUsers(query, limit, offset)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "query": query}
+		logger.Error("failed search users", args)
+		return nil, err
+	}
+
+	result := make([]Account, 0, len(users))
+	for _, u := range users {
+		result = append(result, newAccount(u))
+	}
+
+	return result, nil
+}
+
+func GetAccount(userID int) (Account, error) {
+	u, err := getUser(userID)
+	if err != nil {
+		return Account{}, err
+	}
+
+	return newAccount(u), nil
+}
+
+// Block stops the user from logging in and signs them out of all sessions.
+// Issued access tokens are rejected by the status check of authorized routes.
+func Block(actorID int, userID int) error {
+	u, err := getUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if u.Status == store.UserStatusDeleted {
+		return ErrorUserNotFound
+	}
+
+	return setStatus(actorID, userID, store.UserStatusBlocked)
+}
+
+func Unblock(actorID int, userID int) error {
+	u, err := getUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if u.Status == store.UserStatusDeleted {
+		return ErrorUserNotFound
+	}
+
+	return setStatus(actorID, userID, store.UserStatusActive)
+}
+
+// Delete deactivates the account for good. The user row is kept, as orders,
+// withdrawals and adjustments refer to it.
+func Delete(actorID int, userID int) error {
+	if _, err := getUser(userID); err != nil {
+		return err
+	}
+
+	return setStatus(actorID, userID, store.UserStatusDeleted)
+}
+
+// SetRole changes the user role. Issued access tokens are rejected from now
+// on, so the user has to refresh them to get tokens with the new role.
+func SetRole(actorID int, userID int, role string) error {
+	if !auth.IsValidRole(role) {
+		return ErrorInvalidRole
+	}
+
+	if _, err := getUser(userID); err != nil {
+		return err
+	}
+
+	_, changed, err := store.SetUserRole(userID, role)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed set user role", args)
+		return err
+	}
+
+	if !changed {
+		return nil
+	}
+	verifiedTokens.invalidateUser(userID)
+
+	args := map[string]interface{}{"userID": userID, "actorID": actorID, "role": role}
+	logger.Security("user_role_changed", args)
+
+	return nil
+}
+
+func setStatus(actorID int, userID int, status string) error {
+	changed, err := store.SetUserStatus(userID, status)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID, "status": status}
+		logger.Error("failed set user status", args)
+		return err
+	}
+
+	statuses.invalidate(userID)
+
+	if !changed {
+		return nil
+	}
+
+	if status != store.UserStatusActive {
+		_, err = store.RevokeUserSessions(userID, "")
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": userID}
+			logger.Error("failed revoke sessions of inactive user", args)
+			return err
+		}
+		verifiedTokens.invalidateUser(userID)
+	}
+
+	args := map[string]interface{}{"userID": userID, "actorID": actorID, "status": status}
+	logger.Security("user_status_changed", args)
+
+	return nil
+}
+
+func getUser(userID int) (store.User, error) {
+	u, err := store.GetUserByID(userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return store.User{}, ErrorUserNotFound
+		}
+
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed get user by id", args)
+		return store.User{}, err
+	}
+
+	return u, nil
+}
+
+func newAccount(u store.User) Account {
+	return Account{
+		ID:            u.ID,
+		Login:         u.Login,
+		Email:         u.Email,
+		Role:          userRole(u),
+		Status:        u.Status,
+		TOTPEnabled:   u.TOTPEnabled,
+		LoginConflict: u.LoginConflict,
+	}
+}
@@ -0,0 +1,229 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+
+	apiKeyPrefix          = "gm_"
+	defaultAPIKeyLifetime = 90 * 24 * time.Hour
+	maxAPIKeyLifetime     = 365 * 24 * time.Hour
+	maxAPIKeyNameLength   = 64
+
+	// apiKeyTouchInterval matches the interval of touchAPIKeySQL, so a key in
+	// steady use costs no database write on most requests.
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyAuthenticator verifies API keys from the X-API-Key header. Keys act
+// on behalf of their user with the user role only, limited by their scopes.
+type APIKeyAuthenticator struct{}
+
+func NewAPIKeyAuthenticator() *APIKeyAuthenticator {
+	return &APIKeyAuthenticator{}
+}
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (auth.Principal, error) {
+	key := strings.TrimSpace(r.Header.Get(APIKeyHeader))
+	if key == "" {
+		return auth.Principal{}, auth.ErrorNoCredentials
+	}
+
+	stored, err := store.GetAPIKeyByHash(hashToken(key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return auth.Principal{}, ErrorInvalidAPIKey
+		}
+
+		return auth.Principal{}, err
+	}
+
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return auth.Principal{}, ErrorInvalidAPIKey
+	}
+
+	if stored.LastUsedAt == nil || time.Since(*stored.LastUsedAt) >= apiKeyTouchInterval {
+		if err = store.TouchAPIKey(stored.ID); err != nil {
+			args := map[string]interface{}{"error": err.Error(), "keyID": stored.ID}
+			logger.Error("failed update api key last used time", args)
+		}
+	}
+
+	return auth.Principal{
+		UserID:     stored.UserID,
+		Roles:      []string{auth.RoleUser},
+		TokenID:    strconv.Itoa(stored.ID),
+		Credential: auth.CredentialAPIKey,
+		ExpiresAt:  stored.ExpiresAt,
+		Scopes:     stored.Scopes,
+	}, nil
+}
+
+// CreateAPIKey issues a key for the user. The returned secret is not stored
+// and cannot be shown again. A zero lifetime means the default lifetime.
+// Keys issued for another user are limited to read scopes, so staff cannot
+// place orders or spend points in the user's name.
+func CreateAPIKey(actorID int, userID int, name string, scopes []string, lifetime time.Duration) (APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if lifetime == 0 {
+		lifetime = defaultAPIKeyLifetime
+	}
+
+	if validationErrors := validateAPIKey(name, scopes, lifetime, actorID == userID); len(validationErrors) > 0 {
+		return APIKey{}, "", validationErrors
+	}
+
+	prefix, err := newRandomToken(4)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	secret, err := newRandomToken(32)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	key := apiKeyPrefix + prefix + "_" + secret
+	created, err := store.CreateAPIKey(store.APIKey{
+		UserID:    userID,
+		Name:      name,
+		KeyPrefix: apiKeyPrefix + prefix,
+		KeyHash:   hashToken(key),
+		Scopes:    scopes,
+		CreatedBy: actorID,
+		ExpiresAt: time.Now().Add(lifetime),
+	})
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed create api key", args)
+		return APIKey{}, "", err
+	}
+
+	args := map[string]interface{}{"userID": userID, "actorID": actorID, "keyID": created.ID, "scopes": scopes}
+	logger.Security("api_key_created", args)
+
+	return newAPIKey(created), key, nil
+}
+
+func ListAPIKeys(userID int) ([]APIKey, error) {
+	keys, err := store.GetAPIKeysByUserID(userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed get user api keys", args)
+		return nil, err
+	}
+
+	result := make([]APIKey, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, newAPIKey(k))
+	}
+
+	return result, nil
+}
+
+func RevokeAPIKey(actorID int, userID int, keyID int) error {
+	revoked, err := store.RevokeAPIKey(keyID, userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed revoke api key", args)
+		return err
+	}
+
+	if !revoked {
+		return ErrorAPIKeyNotFound
+	}
+
+	args := map[string]interface{}{"userID": userID, "actorID": actorID, "keyID": keyID}
+	logger.Security("api_key_revoked", args)
+
+	return nil
+}
+
+func validateAPIKey(name string, scopes []string, lifetime time.Duration, own bool) ValidationErrors {
+	var validationErrors ValidationErrors
+
+	if name == "" || len([]rune(name)) > maxAPIKeyNameLength {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "name",
+			Code:    "invalid_length",
+			Message: "name must be 1 to " + strconv.Itoa(maxAPIKeyNameLength) + " characters long",
+		})
+	}
+
+	if len(scopes) == 0 {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "scopes",
+			Code:    "required",
+			Message: "at least one scope is required",
+		})
+	}
+
+	for _, scope := range scopes {
+		if !isKnownScope(scope) {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "scopes",
+				Code:    "unknown_scope",
+				Message: "unknown scope " + scope,
+			})
+		} else if !own && !isReadScope(scope) {
+			validationErrors = append(validationErrors, ValidationError{
+				Field:   "scopes",
+				Code:    "owner_only_scope",
+				Message: "only the account owner can issue keys with scope " + scope,
+			})
+		}
+	}
+
+	if lifetime < 0 || lifetime > maxAPIKeyLifetime {
+		validationErrors = append(validationErrors, ValidationError{
+			Field:   "expires_in_days",
+			Code:    "out_of_range",
+			Message: "key lifetime must be 1 to " + strconv.Itoa(int(maxAPIKeyLifetime.Hours()/24)) + " days",
+		})
+	}
+
+	return validationErrors
+}
+
+func isKnownScope(scope string) bool {
+	for _, s := range auth.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isReadScope(scope string) bool {
+	for _, s := range auth.ReadScopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newAPIKey(k store.APIKey) APIKey {
+	return APIKey{
+		ID:         k.ID,
+		Name:       k.Name,
+		Prefix:     k.KeyPrefix,
+		Scopes:     k.Scopes,
+		CreatedAt:  k.CreatedAt,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+	}
+}
@@ -0,0 +1,145 @@
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/keyring"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// JWTAuthenticator verifies access tokens from the "Authorization: Bearer"
+// header or the token cookie.
+type JWTAuthenticator struct {
+	env config.AppEnvironment
+}
+
+func NewJWTAuthenticator(env config.AppEnvironment) *JWTAuthenticator {
+	return &JWTAuthenticator{env: env}
+}
+
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (auth.Principal, error) {
+	tokenString, credential := requestToken(r, a.env.GetAuthTokenPrecedence())
+	if tokenString == "" {
+		return auth.Principal{}, auth.ErrorNoCredentials
+	}
+
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return auth.Principal{}, err
+	}
+
+	if claims.ID == "" || !verifiedTokens.valid(claims.ID) {
+		if err := checkToken(claims); err != nil {
+			return auth.Principal{}, err
+		}
+
+		if claims.ID != "" {
+			verifiedTokens.add(claims.ID, claims.UserID, claims.SessionID)
+		}
+	}
+
+	return auth.Principal{
+		UserID:     claims.UserID,
+		Roles:      auth.ImpliedRoles(claims.Role),
+		TokenID:    claims.ID,
+		SessionID:  claims.SessionID,
+		Credential: credential,
+		ExpiresAt:  claims.ExpiresAt.Time,
+	}, nil
+}
+
+// parseToken verifies the token signature and its claims.
+func parseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, keyring.Keyfunc)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed parse token", args)
+		return nil, err
+	}
+
+	if !token.Valid || claims.UserID == 0 || claims.ExpiresAt == nil || !claims.VerifyAudience(accessAudience, true) {
+		return nil, errors.New("invalid token or userID param")
+	}
+
+	return claims, nil
+}
+
+// checkToken checks the token against the user token version, the revocation
+// list and its session, and records the session activity.
+func checkToken(claims *Claims) error {
+	version, err := store.GetUserTokenVersion(claims.UserID)
+	if err != nil {
+		return err
+	}
+
+	if claims.TokenVersion != version {
+		return ErrorTokenRevoked
+	}
+
+	if claims.ID != "" {
+		revoked, err := store.IsTokenRevoked(claims.ID)
+		if err != nil {
+			return err
+		}
+
+		if revoked {
+			return ErrorTokenRevoked
+		}
+	}
+
+	if claims.SessionID != "" {
+		active, err := store.TouchSession(claims.SessionID, claims.UserID)
+		if err != nil {
+			return err
+		}
+
+		if !active {
+			return ErrorTokenRevoked
+		}
+	}
+
+	return nil
+}
+
+// requestToken returns the JWT from the "Authorization: Bearer" header or the
+// token cookie together with the credential type. When both are present the
+// configured precedence decides.
+func requestToken(r *http.Request, precedence string) (string, string) {
+	bearer := BearerToken(r)
+
+	var cookieValue string
+	if cookie, err := auth.Cookie(r, auth.TokenCookie); err == nil {
+		cookieValue = cookie.Value
+	}
+
+	if precedence == config.AuthTokenPrecedenceCookie && cookieValue != "" {
+		return cookieValue, auth.CredentialCookie
+	}
+
+	if bearer != "" {
+		return bearer, auth.CredentialBearer
+	}
+
+	if cookieValue != "" {
+		return cookieValue, auth.CredentialCookie
+	}
+
+	return "", ""
+}
+
+// BearerToken returns the token from the "Authorization: Bearer" header.
+func BearerToken(r *http.Request) string {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,195 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+var ErrorPasswordMismatch = errors.New("password does not match")
+var ErrorUnknownPasswordHash = errors.New("unknown password hash format")
+
+// dummyHash is hashed with the configured policy on first use and checked
+// against for logins which do not exist.
+var dummyHash struct {
+	once sync.Once
+	hash string
+}
+
+// PasswordHasher hashes passwords according to the configured policy. Hashes
+// are self-describing (bcrypt "$2a$..." and PHC "$argon2id$..." strings), so the
+// algorithm and its parameters are stored together with each hash.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// NeedsRehash reports whether the hash was produced by another algorithm
+	// or with parameters different from the current policy.
+	NeedsRehash(encoded string) bool
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+type argon2idHasher struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+}
+
+// NewPasswordHasher returns the hasher of the current password policy.
+func NewPasswordHasher(env config.AppEnvironment) PasswordHasher {
+	if env.GetPasswordHasher() == config.PasswordHasherBcrypt {
+		cost := env.GetBcryptCost()
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			args := map[string]interface{}{"cost": cost}
+			logger.Error("invalid bcrypt cost, default cost is used", args)
+			cost = bcrypt.DefaultCost
+		}
+
+		return &bcryptHasher{cost: cost}
+	}
+
+	return &argon2idHasher{
+		memory:      uint32(env.GetArgon2Memory()),
+		iterations:  uint32(env.GetArgon2Iterations()),
+		parallelism: uint8(env.GetArgon2Parallelism()),
+	}
+}
+
+// verifyPassword checks the password against a hash of any supported algorithm.
+// verifyDummyPassword takes as long as a password check of an existing user,
+// so the response time does not reveal whether a login exists.
+func verifyDummyPassword(password string) {
+	dummyHash.once.Do(func() {
+		hash, err := encodePassword("dummy password")
+		if err != nil {
+			return
+		}
+		dummyHash.hash = hash
+	})
+
+	if dummyHash.hash != "" {
+		verifyPassword(dummyHash.hash, password)
+	}
+}
+
+func verifyPassword(encoded string, password string) error {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return err
+		}
+
+		other := argon2.IDKey([]byte(password), salt, params.iterations, params.memory, params.parallelism, uint32(len(key)))
+		if subtle.ConstantTimeCompare(key, other) != 1 {
+			return ErrorPasswordMismatch
+		}
+
+		return nil
+	case strings.HasPrefix(encoded, "$2"):
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrorPasswordMismatch
+		}
+
+		return err
+	default:
+		return ErrorUnknownPasswordHash
+	}
+}
+
+func (h *bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+func (h *bcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	if err != nil {
+		return true
+	}
+
+	return cost != h.cost
+}
+
+func (h *argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.iterations, h.memory, h.parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s", argon2.Version, h.memory, h.iterations, h.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h *argon2idHasher) NeedsRehash(encoded string) bool {
+	params, _, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return *params != *h || len(key) != argon2KeyLength
+}
+
+func decodeArgon2id(encoded string) (*argon2idHasher, []byte, []byte, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return nil, nil, nil, ErrorUnknownPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("unsupported argon2 version %d", version)
+	}
+
+	params := &argon2idHasher{}
+	_, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.iterations, &params.parallelism)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// argon2.IDKey panics on zero rounds or threads, and a corrupted or
+	// crafted hash must not make a login cost more than the configuration may
+	// ask for. Sscanf already rejects a parallelism beyond uint8.
+	if params.memory == 0 || params.memory > config.MaxArgon2Memory ||
+		params.iterations == 0 || params.iterations > config.MaxArgon2Iterations || params.parallelism == 0 {
+		return nil, nil, nil, fmt.Errorf("invalid argon2 parameters %q", parts[3])
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return params, salt, key, nil
+}
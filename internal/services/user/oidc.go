@@ -0,0 +1,272 @@
+package user
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/keyring"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/oidc"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	oidcAudience      = "oidc"
+	oidcStateLifetime = 10 * time.Minute
+
+	// oidcPassword is never produced by a password hasher, so provisioned
+	// users can log in only through the identity provider until they reset
+	// their password.
+	oidcPassword = "!oidc"
+)
+
+// oidcStateClaims carry the state, nonce and PKCE verifier of a pending
+// login between the redirect to the provider and the callback.
+type oidcStateClaims struct {
+	jwt.RegisteredClaims
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+}
+
+// StartOIDCLogin returns the provider login URL and a signed state token which
+// the client must present to CompleteOIDCLogin together with the callback
+// parameters.
+func StartOIDCLogin() (OIDCLogin, error) {
+	if !oidc.Enabled() {
+		return OIDCLogin{}, oidc.ErrorDisabled
+	}
+
+	claims := oidcStateClaims{}
+	for _, v := range []*string{&claims.ID, &claims.State, &claims.Nonce, &claims.Verifier} {
+		token, err := newRandomToken(32)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error()}
+			logger.Error("failed generate oidc state", args)
+			return OIDCLogin{}, err
+		}
+		*v = token
+	}
+
+	expiresAt := time.Now().Add(oidcStateLifetime)
+	claims.Audience = jwt.ClaimStrings{oidcAudience}
+	claims.IssuedAt = jwt.NewNumericDate(time.Now())
+	claims.ExpiresAt = jwt.NewNumericDate(expiresAt)
+
+	url, err := oidc.AuthCodeURL(claims.State, claims.Nonce, claims.Verifier)
+	if err != nil {
+		return OIDCLogin{}, err
+	}
+
+	stateToken, err := keyring.Sign(claims)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed sign oidc state", args)
+		return OIDCLogin{}, err
+	}
+
+	return OIDCLogin{URL: url, StateToken: stateToken, ExpiresAt: expiresAt}, nil
+}
+
+// CompleteOIDCLogin exchanges the authorization code, finds or provisions the
+// user linked to the provider subject and starts a session.
+func CompleteOIDCLogin(stateToken string, state string, code string, client Client) (Tokens, error) {
+	claims := &oidcStateClaims{}
+	token, err := jwt.ParseWithClaims(stateToken, claims, keyring.Keyfunc)
+	if err != nil || !token.Valid || !claims.VerifyAudience(oidcAudience, true) || claims.State == "" ||
+		claims.State != state || code == "" {
+		args := map[string]interface{}{"ip": client.IP}
+		logger.Security("oidc_state_mismatch", args)
+		return Tokens{}, ErrorInvalidOIDCState
+	}
+
+	revoked, err := store.IsTokenRevoked(claims.ID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if revoked {
+		return Tokens{}, ErrorInvalidOIDCState
+	}
+
+	if err = store.RevokeToken(claims.ID, claims.ExpiresAt.Time); err != nil {
+		return Tokens{}, err
+	}
+
+	identity, err := oidc.Exchange(code, claims.Verifier, claims.Nonce)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "ip": client.IP}
+		logger.Security("oidc_login_failed", args)
+		return Tokens{}, err
+	}
+
+	u, err := identityUser(identity)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if err = statusError(u.Status); err != nil {
+		args := map[string]interface{}{"userID": u.ID, "ip": client.IP, "status": u.Status}
+		logger.Security("inactive_user_login", args)
+		return Tokens{}, err
+	}
+
+	u, err = syncIdentityRole(u, identity)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	args := map[string]interface{}{"userID": u.ID, "ip": client.IP, "subject": identity.Subject}
+	logger.Security("oidc_login", args)
+
+	return startSession(u, client)
+}
+
+// identityUser returns the user linked to the identity and provisions a new
+// one on the first login. Existing local accounts are never linked by login
+// or email, as the provider does not prove their ownership.
+func identityUser(identity oidc.Identity) (store.User, error) {
+	u, err := store.GetUserByIdentity(identity.Issuer, identity.Subject)
+	if err == nil {
+		return u, nil
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return store.User{}, err
+	}
+
+	login, normalized, err := identityLogin(identity)
+	if err != nil {
+		return store.User{}, err
+	}
+
+	email := ""
+	if identity.EmailVerified && len(validateEmail(identity.Email)) == 0 {
+		email = identity.Email
+	}
+
+	u, err = store.CreateIdentityUser(login, normalized, oidcPassword, email, identity.Issuer, identity.Subject)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "subject": identity.Subject}
+		logger.Error("failed provision oidc user", args)
+		return store.User{}, err
+	}
+
+	args := map[string]interface{}{"userID": u.ID, "login": login, "subject": identity.Subject}
+	logger.Security("oidc_user_provisioned", args)
+
+	return u, nil
+}
+
+// identityLogin picks a free login for a provisioned user and returns it
+// with its normalized form. The preferred username is used when it is valid
+// and free; otherwise the login is derived from the subject, which is unique
+// per provider.
+func identityLogin(identity oidc.Identity) (string, string, error) {
+	candidates := []string{displayLogin(identity.PreferredUsername)}
+
+	sum := sha256.Sum256([]byte(identity.Issuer + "|" + identity.Subject))
+	candidates = append(candidates, "sso-"+hex.EncodeToString(sum[:])[:16])
+
+	for _, login := range candidates {
+		normalized := NormalizeLogin(login)
+		if normalized == "" || len(validateLogin(normalized)) > 0 {
+			continue
+		}
+
+		exists, err := store.HasUserByLogin(normalized)
+		if err != nil {
+			return "", "", err
+		}
+
+		if !exists {
+			return login, normalized, nil
+		}
+	}
+
+	return "", "", ErrorUserExists
+}
+
+// syncIdentityRole applies the configured group-to-role mapping. Without a
+// mapping roles are managed in the admin API only.
+func syncIdentityRole(u store.User, identity oidc.Identity) (store.User, error) {
+	cnf, err := config.GetAppConfig()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+		return store.User{}, err
+	}
+
+	mapping := parseRoleMapping(cnf.GetOIDCRoleMapping())
+	if len(mapping) == 0 {
+		return u, nil
+	}
+
+	role := mappedRole(identity.Groups, mapping)
+	if role == u.Role {
+		return u, nil
+	}
+
+	version, changed, err := store.SetUserRole(u.ID, role)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed sync oidc user role", args)
+		return store.User{}, err
+	}
+
+	if !changed {
+		return u, nil
+	}
+	verifiedTokens.invalidateUser(u.ID)
+
+	args := map[string]interface{}{"userID": u.ID, "from": u.Role, "to": role}
+	logger.Security("oidc_role_synced", args)
+
+	u.Role = role
+	u.TokenVersion = version
+	return u, nil
+}
+
+// mappedRole returns the most privileged role mapped from the groups. Users
+// outside every mapped group get the default role.
+func mappedRole(groups []string, mapping map[string]string) string {
+	role := auth.RoleUser
+	for _, group := range groups {
+		mapped, ok := mapping[group]
+		if ok && len(auth.ImpliedRoles(mapped)) > len(auth.ImpliedRoles(role)) {
+			role = mapped
+		}
+	}
+
+	return role
+}
+
+// parseRoleMapping parses "group=role,other=role" pairs. Pairs with unknown
+// roles are ignored.
+func parseRoleMapping(value string) map[string]string {
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		group, role, ok := strings.Cut(pair, "=")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if !ok || group == "" {
+			continue
+		}
+
+		if !auth.IsValidRole(role) {
+			args := map[string]interface{}{"group": group, "role": role}
+			logger.Error("invalid role in oidc role mapping", args)
+			continue
+		}
+
+		mapping[group] = role
+	}
+
+	return mapping
+}
@@ -0,0 +1,310 @@
+package user
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/keyring"
+	"github.com/MagicNetLab/go-diploma/internal/services/oidc"
+	"github.com/MagicNetLab/go-diploma/internal/services/oidc/oidctest"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// testDatabaseURIKey names the database the login flow tests run against.
+// They are skipped when it is not set, as the flow stores users, identities
+// and revoked state tokens.
+const testDatabaseURIKey = "TEST_DATABASE_URI"
+
+var oidcSetup struct {
+	once sync.Once
+	idp  *oidctest.Provider
+	err  error
+}
+
+var testClient = Client{IP: "127.0.0.1", UserAgent: "oidc-test"}
+
+func TestParseRoleMapping(t *testing.T) {
+	got := parseRoleMapping(" support-team = support ,platform-admins=admin,broken,=admin,guests=owner")
+	want := map[string]string{"support-team": auth.RoleSupport, "platform-admins": auth.RoleAdmin}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mapping = %v, want %v", got, want)
+	}
+}
+
+func TestMappedRole(t *testing.T) {
+	mapping := map[string]string{"support-team": auth.RoleSupport, "platform-admins": auth.RoleAdmin}
+
+	tests := []struct {
+		name   string
+		groups []string
+		want   string
+	}{
+		{name: "no groups", groups: nil, want: auth.RoleUser},
+		{name: "unmapped group", groups: []string{"staff"}, want: auth.RoleUser},
+		{name: "mapped group", groups: []string{"staff", "support-team"}, want: auth.RoleSupport},
+		{name: "most privileged wins", groups: []string{"platform-admins", "support-team"}, want: auth.RoleAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mappedRole(tt.groups, mapping); got != tt.want {
+				t.Errorf("role = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompleteOIDCLoginProvisionsAndLinksBySubject(t *testing.T) {
+	idp := setupOIDC(t)
+	subject, login := randomName(t), "oidc-"+randomName(t)
+
+	if _, err := oidcLogin(t, idp, jwt.MapClaims{"sub": subject, "preferred_username": login}); err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+
+	provisioned, err := store.GetUserByIdentity(idp.Issuer(), subject)
+	if err != nil {
+		t.Fatalf("get provisioned user: %v", err)
+	}
+
+	if provisioned.Login != login {
+		t.Errorf("login = %q, want %q", provisioned.Login, login)
+	}
+
+	// A renamed account at the provider keeps its link.
+	if _, err = oidcLogin(t, idp, jwt.MapClaims{"sub": subject, "preferred_username": "renamed-" + login}); err != nil {
+		t.Fatalf("second login: %v", err)
+	}
+
+	linked, err := store.GetUserByIdentity(idp.Issuer(), subject)
+	if err != nil {
+		t.Fatalf("get linked user: %v", err)
+	}
+
+	if linked.ID != provisioned.ID || linked.Login != login {
+		t.Errorf("linked user = %d %q, want %d %q", linked.ID, linked.Login, provisioned.ID, login)
+	}
+
+	// Another subject claiming the same username is never linked to the
+	// existing account.
+	other := randomName(t)
+	if _, err = oidcLogin(t, idp, jwt.MapClaims{"sub": other, "preferred_username": login}); err != nil {
+		t.Fatalf("other subject login: %v", err)
+	}
+
+	otherUser, err := store.GetUserByIdentity(idp.Issuer(), other)
+	if err != nil {
+		t.Fatalf("get other user: %v", err)
+	}
+
+	if otherUser.ID == provisioned.ID || otherUser.Login == login {
+		t.Errorf("other subject got user %d %q", otherUser.ID, otherUser.Login)
+	}
+}
+
+func TestCompleteOIDCLoginStateReuse(t *testing.T) {
+	idp := setupOIDC(t)
+	claims := jwt.MapClaims{"sub": randomName(t)}
+
+	start, err := StartOIDCLogin()
+	if err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+
+	code, state, err := idp.Authorize(start.URL, claims)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	if _, err = CompleteOIDCLogin(start.StateToken, state, code, testClient); err != nil {
+		t.Fatalf("complete login: %v", err)
+	}
+
+	code, state, err = idp.Authorize(start.URL, claims)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	redeemed := idp.Redeemed()
+	if _, err = CompleteOIDCLogin(start.StateToken, state, code, testClient); !errors.Is(err, ErrorInvalidOIDCState) {
+		t.Errorf("err = %v, want %v", err, ErrorInvalidOIDCState)
+	}
+
+	if idp.Redeemed() != redeemed {
+		t.Error("code of a reused state token was exchanged")
+	}
+}
+
+func TestCompleteOIDCLoginStateMismatch(t *testing.T) {
+	idp := setupOIDC(t)
+
+	start, err := StartOIDCLogin()
+	if err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+
+	code, _, err := idp.Authorize(start.URL, jwt.MapClaims{"sub": randomName(t)})
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	if _, err = CompleteOIDCLogin(start.StateToken, "other-state", code, testClient); !errors.Is(err, ErrorInvalidOIDCState) {
+		t.Errorf("err = %v, want %v", err, ErrorInvalidOIDCState)
+	}
+}
+
+func TestCompleteOIDCLoginInvalidIDToken(t *testing.T) {
+	idp := setupOIDC(t)
+	subject := randomName(t)
+
+	_, err := oidcLogin(t, idp, jwt.MapClaims{"sub": subject, "nonce": "other"})
+	if !errors.Is(err, oidc.ErrorInvalidIDToken) {
+		t.Fatalf("err = %v, want %v", err, oidc.ErrorInvalidIDToken)
+	}
+
+	if _, err = store.GetUserByIdentity(idp.Issuer(), subject); err == nil {
+		t.Error("user was provisioned from a rejected id token")
+	}
+}
+
+func TestCompleteOIDCLoginSyncsRole(t *testing.T) {
+	idp := setupOIDC(t)
+	subject := randomName(t)
+
+	steps := []struct {
+		groups []string
+		want   string
+	}{
+		{groups: []string{"platform-admins"}, want: auth.RoleAdmin},
+		{groups: []string{"support-team", "staff"}, want: auth.RoleSupport},
+		{groups: nil, want: auth.RoleUser},
+	}
+
+	for _, step := range steps {
+		if _, err := oidcLogin(t, idp, jwt.MapClaims{"sub": subject, "groups": step.groups}); err != nil {
+			t.Fatalf("login with groups %v: %v", step.groups, err)
+		}
+
+		u, err := store.GetUserByIdentity(idp.Issuer(), subject)
+		if err != nil {
+			t.Fatalf("get user: %v", err)
+		}
+
+		if u.Role != step.want {
+			t.Errorf("groups %v: role = %q, want %q", step.groups, u.Role, step.want)
+		}
+	}
+}
+
+// setupOIDC configures the services against the test database and an
+// in-process identity provider once per test binary.
+func setupOIDC(t *testing.T) *oidctest.Provider {
+	t.Helper()
+
+	dbURI := os.Getenv(testDatabaseURIKey)
+	if dbURI == "" {
+		t.Skipf("%s is not set", testDatabaseURIKey)
+	}
+
+	oidcSetup.once.Do(func() {
+		idp, err := oidctest.NewProvider("gophermart")
+		if err != nil {
+			oidcSetup.err = err
+			return
+		}
+		oidcSetup.idp = idp
+
+		values := map[string]string{
+			"APP_MODE":               config.AppModeDevelopment,
+			"RUN_ADDRESS":            "localhost:0",
+			"DATABASE_URI":           dbURI,
+			"ACCRUAL_SYSTEM_ADDRESS": "http://localhost:0",
+			"JWT_SECRET":             "oidc-test-secret",
+			"OIDC_ISSUER":            idp.Issuer(),
+			"OIDC_CLIENT_ID":         "gophermart",
+			"OIDC_REDIRECT_URL":      "http://localhost/api/user/oidc/callback",
+			"OIDC_ROLE_CLAIM":        "groups",
+			"OIDC_ROLE_MAPPING":      "support-team=support,platform-admins=admin",
+		}
+		for k, v := range values {
+			if err = os.Setenv(k, v); err != nil {
+				oidcSetup.err = err
+				return
+			}
+		}
+
+		cnf, err := config.GetAppConfig()
+		if err != nil {
+			oidcSetup.err = err
+			return
+		}
+
+		if err = keyring.Init(cnf); err != nil {
+			oidcSetup.err = err
+			return
+		}
+
+		// Migrations are read relative to the working directory.
+		wd, err := os.Getwd()
+		if err != nil {
+			oidcSetup.err = err
+			return
+		}
+
+		if err = os.Chdir("../../.."); err != nil {
+			oidcSetup.err = err
+			return
+		}
+		oidcSetup.err = store.Init(cnf)
+		if err = os.Chdir(wd); err != nil && oidcSetup.err == nil {
+			oidcSetup.err = err
+		}
+		if oidcSetup.err != nil {
+			return
+		}
+
+		oidcSetup.err = oidc.Init(cnf)
+	})
+
+	if oidcSetup.err != nil {
+		t.Fatalf("setup oidc login: %v", oidcSetup.err)
+	}
+
+	return oidcSetup.idp
+}
+
+func oidcLogin(t *testing.T, idp *oidctest.Provider, claims jwt.MapClaims) (Tokens, error) {
+	t.Helper()
+
+	start, err := StartOIDCLogin()
+	if err != nil {
+		t.Fatalf("start login: %v", err)
+	}
+
+	code, state, err := idp.Authorize(start.URL, claims)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+
+	return CompleteOIDCLogin(start.StateToken, state, code, testClient)
+}
+
+func randomName(t *testing.T) string {
+	t.Helper()
+
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		t.Fatalf("random name: %v", err)
+	}
+
+	return hex.EncodeToString(b)
+}
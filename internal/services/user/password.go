@@ -0,0 +1,89 @@
+package user
+
+import (
+	"errors"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+)
+
+// ChangePassword replaces the user password after checking the current one.
+// All previously issued tokens are invalidated, and a new session is started
+// for the caller.
+func ChangePassword(userID int, currentPassword string, newPassword string, client Client) (Tokens, error) {
+	ip := client.IP
+	cnf, err := config.GetAppConfig()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+		return Tokens{}, err
+	}
+
+	u, err := store.GetUserByID(userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed get user by id to change password", args)
+		return Tokens{}, err
+	}
+
+	login := NormalizeLogin(u.Login)
+	if err = throttle.check(login, ip); err != nil {
+		return Tokens{}, err
+	}
+
+	err = verifyPassword(u.Password, currentPassword)
+	if err != nil {
+		if !errors.Is(err, ErrorPasswordMismatch) {
+			return Tokens{}, err
+		}
+
+		throttle.failure(login, ip)
+		return Tokens{}, ValidationErrors{{
+			Field:   "current_password",
+			Code:    "mismatch",
+			Message: "current password is incorrect",
+		}}
+	}
+
+	if validationErrors := validatePassword(cnf, newPassword, login); len(validationErrors) > 0 {
+		for i := range validationErrors {
+			validationErrors[i].Field = "new_password"
+		}
+		return Tokens{}, validationErrors
+	}
+
+	hash, err := encodePassword(newPassword)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	version, err := store.ChangeUserPassword(u.ID, hash)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed change user password", args)
+		return Tokens{}, err
+	}
+	verifiedTokens.invalidateUser(u.ID)
+
+	err = store.RevokeUserRefreshTokens(u.ID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed revoke user refresh tokens", args)
+		return Tokens{}, err
+	}
+
+	_, err = store.RevokeUserSessions(u.ID, "")
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed revoke user sessions", args)
+		return Tokens{}, err
+	}
+
+	args := map[string]interface{}{"userID": u.ID, "ip": ip}
+	logger.Security("password_changed", args)
+
+	u.TokenVersion = version
+
+	return startSession(u, client)
+}
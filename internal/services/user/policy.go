@@ -0,0 +1,155 @@
+package user
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"net/mail"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	loginMinLength    = 3
+	loginMaxLength    = 64
+	passwordMaxLength = 72 // bcrypt ignores everything after 72 bytes
+)
+
+//go:embed breached_passwords.txt
+var breachedPasswordsList string
+
+var breachedPasswords = parseBreachedPasswords(breachedPasswordsList)
+
+var loginFolder = cases.Fold()
+
+// NormalizeLogin brings the login to the form it is compared in: the
+// displayed login is case-folded, so "Alice" and "alice" are the same login.
+func NormalizeLogin(login string) string {
+	return loginFolder.String(displayLogin(login))
+}
+
+// displayLogin is the login as it is shown to the user: surrounding spaces
+// are trimmed and the string is converted to Unicode NFC, but the case is
+// kept.
+func displayLogin(login string) string {
+	return norm.NFC.String(strings.TrimSpace(login))
+}
+
+// validateLogin checks a normalized login against the allowed charset:
+// letters, digits and the ".", "_", "-", "@" characters.
+func validateLogin(login string) ValidationErrors {
+	var errs ValidationErrors
+
+	length := utf8.RuneCountInString(login)
+	if length < loginMinLength || length > loginMaxLength {
+		errs = append(errs, ValidationError{
+			Field:   "login",
+			Code:    "invalid_length",
+			Message: fmt.Sprintf("login must be from %d to %d characters long", loginMinLength, loginMaxLength),
+		})
+	}
+
+	for _, r := range login {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("._-@", r) {
+			errs = append(errs, ValidationError{
+				Field:   "login",
+				Code:    "invalid_characters",
+				Message: "login may contain only letters, digits and . _ - @ characters",
+			})
+			break
+		}
+	}
+
+	return errs
+}
+
+// validatePassword checks the password against the configured policy.
+func validatePassword(env config.AppEnvironment, password string, login string) ValidationErrors {
+	var errs ValidationErrors
+
+	if utf8.RuneCountInString(password) < env.GetPasswordMinLength() {
+		errs = append(errs, ValidationError{
+			Field:   "password",
+			Code:    "too_short",
+			Message: fmt.Sprintf("password must be at least %d characters long", env.GetPasswordMinLength()),
+		})
+	}
+
+	if len(password) > passwordMaxLength {
+		errs = append(errs, ValidationError{
+			Field:   "password",
+			Code:    "too_long",
+			Message: fmt.Sprintf("password must be at most %d bytes long", passwordMaxLength),
+		})
+	}
+
+	if characterClasses(password) < env.GetPasswordMinClasses() {
+		errs = append(errs, ValidationError{
+			Field: "password",
+			Code:  "too_simple",
+			Message: fmt.Sprintf("password must contain at least %d of: lowercase letters, uppercase letters, digits, symbols",
+				env.GetPasswordMinClasses()),
+		})
+	}
+
+	folded := loginFolder.String(password)
+	if _, ok := breachedPasswords[folded]; ok || folded == login {
+		errs = append(errs, ValidationError{
+			Field:   "password",
+			Code:    "breached",
+			Message: "password is too common, choose another one",
+		})
+	}
+
+	return errs
+}
+
+// validateEmail checks the optional email address of the user.
+func validateEmail(email string) ValidationErrors {
+	if email == "" {
+		return nil
+	}
+
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return ValidationErrors{{Field: "email", Code: "invalid_format", Message: "email address is invalid"}}
+	}
+
+	return nil
+}
+
+func characterClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+
+	return lower + upper + digit + symbol
+}
+
+func parseBreachedPasswords(list string) map[string]struct{} {
+	passwords := make(map[string]struct{})
+
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			passwords[loginFolder.String(line)] = struct{}{}
+		}
+	}
+
+	return passwords
+}
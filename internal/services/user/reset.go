@@ -0,0 +1,167 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/crash"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/notifier"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/jackc/pgx/v5"
+)
+
+// RequestPasswordReset sends a single-use reset token to the user email.
+// Requests are limited per login and client IP. The user is looked up and the
+// message is sent in the background, and failures are only logged, so every
+// login gets the same answer in the same time and the caller cannot find out
+// which logins exist.
+func RequestPasswordReset(login string, client Client) error {
+	displayed := displayLogin(login)
+	login = NormalizeLogin(login)
+	if err := resetLimit.allow(login, client.IP); err != nil {
+		args := map[string]interface{}{"login": login, "ip": client.IP}
+		logger.Security("password_reset_throttled", args)
+		return err
+	}
+
+	crash.Go("password_reset", func() {
+		sendPasswordReset(login, displayed)
+	})
+
+	return nil
+}
+
+// sendPasswordReset issues the reset token and sends it. Unknown logins and
+// users without email are skipped.
+func sendPasswordReset(login string, displayed string) {
+	cnf, err := config.GetAppConfig()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+		return
+	}
+
+	u, err := store.GetUserByLogin(login, displayed)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			args := map[string]interface{}{"login": login}
+			logger.Info("password reset requested for unknown login", args)
+		}
+
+		return
+	}
+
+	if u.Email == "" {
+		args := map[string]interface{}{"userID": u.ID}
+		logger.Info("password reset requested for user without email", args)
+		return
+	}
+
+	token, err := newRandomToken(32)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed generate password reset token", args)
+		return
+	}
+
+	expiresAt := time.Now().Add(cnf.GetPasswordResetTTL())
+	err = store.CreatePasswordReset(u.ID, hashToken(token), expiresAt)
+	if err != nil {
+		return
+	}
+
+	err = notifier.Send(notifier.Message{
+		To:      u.Email,
+		Subject: "Gophermart password reset",
+		Body: fmt.Sprintf("Someone requested a password reset for the account %q.\n\n"+
+			"Reset token: %s\n\nThe token expires at %s. If you did not request the reset, ignore this message.",
+			u.Login, token, expiresAt.Format(time.RFC1123)),
+	})
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed send password reset token", args)
+		return
+	}
+
+	args := map[string]interface{}{"userID": u.ID}
+	logger.Security("password_reset_requested", args)
+}
+
+// ResetPassword sets a new password by the reset token. The token can be used
+// once, and all sessions of the user are invalidated.
+func ResetPassword(token string, password string) error {
+	cnf, err := config.GetAppConfig()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+		return err
+	}
+
+	reset, err := store.GetPasswordResetByHash(hashToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrorInvalidResetToken
+		}
+
+		return err
+	}
+
+	if reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return ErrorInvalidResetToken
+	}
+
+	u, err := store.GetUserByID(reset.UserID)
+	if err != nil {
+		return err
+	}
+
+	if validationErrors := validatePassword(cnf, password, NormalizeLogin(u.Login)); len(validationErrors) > 0 {
+		return validationErrors
+	}
+
+	hash, err := encodePassword(password)
+	if err != nil {
+		return err
+	}
+
+	used, err := store.UsePasswordReset(reset.ID)
+	if err != nil {
+		return err
+	}
+
+	if !used {
+		return ErrorInvalidResetToken
+	}
+
+	_, err = store.ChangeUserPassword(u.ID, hash)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed reset user password", args)
+		return err
+	}
+	verifiedTokens.invalidateUser(u.ID)
+
+	err = store.RevokeUserRefreshTokens(u.ID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed revoke user refresh tokens", args)
+		return err
+	}
+
+	_, err = store.RevokeUserSessions(u.ID, "")
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed revoke user sessions", args)
+		return err
+	}
+
+	Unlock(u.Login)
+
+	args := map[string]interface{}{"userID": u.ID}
+	logger.Security("password_reset", args)
+
+	return nil
+}
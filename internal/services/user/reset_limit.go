@@ -0,0 +1,85 @@
+package user
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	resetLoginLimit = 3
+	resetIPLimit    = 10
+	resetWindow     = time.Hour
+)
+
+// resetWindowState counts password reset requests of one key in the current
+// window.
+type resetWindowState struct {
+	requests int
+	start    time.Time
+}
+
+// resetLimiter caps password reset requests per login and per IP address,
+// so the endpoint cannot be used to flood a mailbox. Requests for unknown
+// logins are counted too, which keeps the answer the same for every login.
+type resetLimiter struct {
+	mu        sync.Mutex
+	windows   map[string]*resetWindowState
+	lastPrune time.Time
+}
+
+var resetLimit = resetLimiter{windows: make(map[string]*resetWindowState)}
+
+func (l *resetLimiter) allow(login string, ip string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	limits := map[string]int{
+		loginKeyPrefix + login: resetLoginLimit,
+		ipKeyPrefix + ip:       resetIPLimit,
+	}
+
+	var retryAfter time.Duration
+	keys := throttleKeys(login, ip)
+	for _, key := range keys {
+		state, ok := l.windows[key]
+		if !ok || now.Sub(state.start) >= resetWindow {
+			continue
+		}
+
+		if wait := state.start.Add(resetWindow).Sub(now); state.requests >= limits[key] && wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &ThrottledError{RetryAfter: retryAfter, Reason: ErrorResetThrottled}
+	}
+
+	for _, key := range keys {
+		state, ok := l.windows[key]
+		if !ok || now.Sub(state.start) >= resetWindow {
+			state = &resetWindowState{start: now}
+			l.windows[key] = state
+		}
+
+		state.requests++
+	}
+
+	return nil
+}
+
+func (l *resetLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < resetWindow {
+		return
+	}
+
+	for key, state := range l.windows {
+		if now.Sub(state.start) >= resetWindow {
+			delete(l.windows, key)
+		}
+	}
+	l.lastPrune = now
+}
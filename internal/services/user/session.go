@@ -0,0 +1,169 @@
+package user
+
+import (
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+)
+
+// ListSessions returns active sessions of the user. The session of the
+// current request is flagged as current.
+func ListSessions(userID int, currentSessionID string) ([]Session, error) {
+	sessions, err := store.GetUserSessions(userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed get user sessions", args)
+		return nil, err
+	}
+
+	result := make([]Session, 0, len(sessions))
+	for _, s := range sessions {
+		result = append(result, Session{
+			ID:        s.ID,
+			IP:        s.IP,
+			UserAgent: s.UserAgent,
+			CreatedAt: s.CreatedAt,
+			LastSeen:  s.LastSeen,
+			Current:   s.ID == currentSessionID,
+		})
+	}
+
+	return result, nil
+}
+
+// RevokeSession signs the user out of one session. Access tokens of the
+// session are rejected by the authenticator from now on.
+func RevokeSession(userID int, sessionID string) error {
+	revoked, err := store.RevokeSession(userID, sessionID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed revoke user session", args)
+		return err
+	}
+
+	if !revoked {
+		return ErrorSessionNotFound
+	}
+	verifiedTokens.invalidateSession(sessionID)
+
+	args := map[string]interface{}{"userID": userID, "sessionID": sessionID}
+	logger.Security("session_revoked", args)
+
+	return nil
+}
+
+// RevokeOtherSessions signs the user out everywhere except the current session.
+func RevokeOtherSessions(userID int, currentSessionID string) (int, error) {
+	count, err := store.RevokeUserSessions(userID, currentSessionID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed revoke user sessions", args)
+		return 0, err
+	}
+	verifiedTokens.invalidateUser(userID)
+
+	args := map[string]interface{}{"userID": userID, "count": count}
+	logger.Security("sessions_revoked", args)
+
+	return count, nil
+}
+
+// startSession records a new session for the client and issues its first
+// token pair.
+func startSession(u store.User, client Client) (Tokens, error) {
+	sessionID, err := newRandomToken(16)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed generate session id", args)
+		return Tokens{}, err
+	}
+
+	err = store.CreateSession(store.Session{
+		ID:        sessionID,
+		UserID:    u.ID,
+		IP:        client.IP,
+		UserAgent: truncate(client.UserAgent, maxUserAgentLength),
+	})
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed create user session", args)
+		return Tokens{}, err
+	}
+
+	return issueTokens(u, sessionID, time.Now().Add(sessionMaxLifetime()))
+}
+
+// RenewToken implements sliding expiration of cookie sessions: an access
+// token close to its expiry is replaced by a fresh one, as long as the session
+// is younger than the maximum session lifetime. It returns false when no
+// renewal is due. Bearer and API key clients refresh tokens themselves.
+func RenewToken(p auth.Principal) (Tokens, bool, error) {
+	if p.Credential != auth.CredentialCookie || p.SessionID == "" {
+		return Tokens{}, false, nil
+	}
+
+	cnf, err := config.GetAppConfig()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+		return Tokens{}, false, err
+	}
+
+	if time.Until(p.ExpiresAt) > cnf.GetSessionRenewalThreshold() {
+		return Tokens{}, false, nil
+	}
+
+	session, err := store.GetSession(p.SessionID, p.UserID)
+	if err != nil {
+		return Tokens{}, false, err
+	}
+
+	deadline := session.CreatedAt.Add(cnf.GetSessionMaxLifetime())
+	if !deadline.After(p.ExpiresAt) {
+		return Tokens{}, false, nil
+	}
+
+	u, err := store.GetUserByID(p.UserID)
+	if err != nil {
+		return Tokens{}, false, err
+	}
+
+	expiresAt := earliest(time.Now().Add(accessTokenLifetime), deadline)
+	token, err := generateToken(u, p.SessionID, expiresAt)
+	if err != nil {
+		return Tokens{}, false, err
+	}
+
+	return Tokens{AccessToken: token, AccessExpiresAt: expiresAt}, true, nil
+}
+
+func sessionMaxLifetime() time.Duration {
+	cnf, err := config.GetAppConfig()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+		return refreshTokenLifetime
+	}
+
+	return cnf.GetSessionMaxLifetime()
+}
+
+func earliest(a time.Time, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+
+	return a
+}
+
+func truncate(s string, size int) string {
+	runes := []rune(s)
+	if len(runes) <= size {
+		return s
+	}
+
+	return string(runes[:size])
+}
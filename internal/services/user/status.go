@@ -0,0 +1,101 @@
+package user
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/jackc/pgx/v5"
+)
+
+// statusCacheTTL bounds how long another instance may keep serving a user
+// after the account is blocked. The instance which blocks the account drops
+// its cache entry at once.
+const statusCacheTTL = 5 * time.Second
+
+type statusEntry struct {
+	status    string
+	expiresAt time.Time
+}
+
+// statusCache keeps account statuses for a short time, so the status check of
+// every authorized request does not hit the database.
+type statusCache struct {
+	mu        sync.Mutex
+	entries   map[int]statusEntry
+	lastPrune time.Time
+}
+
+var statuses = statusCache{entries: make(map[int]statusEntry)}
+
+// CheckStatus returns an *AccountStatusError when the account may not use
+// the API and ErrorUserNotFound when the user no longer exists.
+func CheckStatus(userID int) error {
+	status, err := statuses.get(userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrorUserNotFound
+		}
+
+		return err
+	}
+
+	return statusError(status)
+}
+
+func statusError(status string) error {
+	if status == store.UserStatusActive {
+		return nil
+	}
+
+	return &AccountStatusError{Status: status}
+}
+
+func (c *statusCache) get(userID int) (string, error) {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	c.mu.Unlock()
+
+	if ok && now.Before(entry.expiresAt) {
+		return entry.status, nil
+	}
+
+	status, err := store.GetUserStatus(userID)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": userID}
+		logger.Error("failed get user status", args)
+		return "", err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prune(now)
+	c.entries[userID] = statusEntry{status: status, expiresAt: now.Add(statusCacheTTL)}
+
+	return status, nil
+}
+
+func (c *statusCache) invalidate(userID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, userID)
+}
+
+func (c *statusCache) prune(now time.Time) {
+	if now.Sub(c.lastPrune) < statusCacheTTL {
+		return
+	}
+
+	for userID, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, userID)
+		}
+	}
+	c.lastPrune = now
+}
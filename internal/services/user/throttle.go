@@ -0,0 +1,158 @@
+package user
+
+import (
+	"sync"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+)
+
+const (
+	loginKeyPrefix = "login:"
+	ipKeyPrefix    = "ip:"
+
+	loginBaseDelay = time.Second
+	loginMaxDelay  = 30 * time.Second
+)
+
+// attemptState keeps failed login statistics for a single login or IP address.
+type attemptState struct {
+	failures    int
+	lastFailure time.Time
+	lockedUntil time.Time
+}
+
+// loginThrottle tracks failed login attempts in memory. Every failure makes the
+// next attempt wait longer, and reaching the configured threshold locks the key
+// for the lockout duration.
+type loginThrottle struct {
+	mu        sync.Mutex
+	attempts  map[string]*attemptState
+	lastPrune time.Time
+}
+
+var throttle = loginThrottle{attempts: make(map[string]*attemptState)}
+
+// Unlock clears failed login attempts and the lockout of the login.
+func Unlock(login string) {
+	login = NormalizeLogin(login)
+
+	throttle.mu.Lock()
+	defer throttle.mu.Unlock()
+
+	delete(throttle.attempts, loginKeyPrefix+login)
+
+	args := map[string]interface{}{"login": login}
+	logger.Security("account_unlocked", args)
+}
+
+func (t *loginThrottle) check(login string, ip string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, key := range throttleKeys(login, ip) {
+		state, ok := t.attempts[key]
+		if !ok {
+			continue
+		}
+
+		if wait := state.waitTime(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &ThrottledError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+func (t *loginThrottle) failure(login string, ip string) {
+	cnf, err := config.GetAppConfig()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	lockout := cnf.GetLoginLockoutDuration()
+	t.prune(now, lockout)
+
+	limits := map[string]int{
+		loginKeyPrefix + login: cnf.GetLoginMaxAttempts(),
+		ipKeyPrefix + ip:       cnf.GetLoginIPMaxAttempts(),
+	}
+	for _, key := range throttleKeys(login, ip) {
+		state, ok := t.attempts[key]
+		if !ok || (now.Sub(state.lastFailure) > lockout && now.After(state.lockedUntil)) {
+			state = &attemptState{}
+			t.attempts[key] = state
+		}
+
+		state.failures++
+		state.lastFailure = now
+
+		if state.failures >= limits[key] {
+			state.lockedUntil = now.Add(lockout)
+			state.failures = 0
+
+			args := map[string]interface{}{"key": key, "login": login, "ip": ip, "locked_until": state.lockedUntil}
+			logger.Security("login_locked", args)
+		}
+	}
+}
+
+func (t *loginThrottle) success(login string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.attempts, loginKeyPrefix+login)
+}
+
+// prune drops stale entries so the map does not grow with every scanned login.
+func (t *loginThrottle) prune(now time.Time, lockout time.Duration) {
+	if now.Sub(t.lastPrune) < lockout {
+		return
+	}
+
+	for key, state := range t.attempts {
+		if now.Sub(state.lastFailure) > lockout && now.After(state.lockedUntil) {
+			delete(t.attempts, key)
+		}
+	}
+	t.lastPrune = now
+}
+
+func (s *attemptState) waitTime(now time.Time) time.Duration {
+	if now.Before(s.lockedUntil) {
+		return s.lockedUntil.Sub(now)
+	}
+
+	if s.failures == 0 {
+		return 0
+	}
+
+	delay := loginBaseDelay << (s.failures - 1)
+	if delay > loginMaxDelay || delay <= 0 {
+		delay = loginMaxDelay
+	}
+
+	return s.lastFailure.Add(delay).Sub(now)
+}
+
+func throttleKeys(login string, ip string) []string {
+	keys := []string{loginKeyPrefix + login}
+	if ip != "" {
+		keys = append(keys, ipKeyPrefix+ip)
+	}
+
+	return keys
+}
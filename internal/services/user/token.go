@@ -0,0 +1,165 @@
+package user
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/jackc/pgx/v5"
+)
+
+// Refresh rotates the refresh token: the presented token is marked as used and
+// a new pair is issued within the same session. Presenting an already used
+// token means it has leaked, so the whole session is revoked.
+func Refresh(refreshToken string) (Tokens, error) {
+	stored, err := store.GetRefreshTokenByHash(hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Tokens{}, ErrorInvalidRefreshToken
+		}
+
+		return Tokens{}, err
+	}
+
+	if stored.RevokedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return Tokens{}, ErrorInvalidRefreshToken
+	}
+
+	if stored.UsedAt != nil {
+		return Tokens{}, revokeReusedSession(stored)
+	}
+
+	marked, err := store.MarkRefreshTokenUsed(stored.ID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if !marked {
+		return Tokens{}, revokeReusedSession(stored)
+	}
+
+	u, err := store.GetUserByID(stored.UserID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if u.Status != store.UserStatusActive {
+		return Tokens{}, ErrorInvalidRefreshToken
+	}
+
+	session, err := store.GetSession(stored.SessionID, u.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Tokens{}, ErrorInvalidRefreshToken
+		}
+
+		return Tokens{}, err
+	}
+
+	deadline := session.CreatedAt.Add(sessionMaxLifetime())
+	if !time.Now().Before(deadline) {
+		args := map[string]interface{}{"userID": u.ID, "sessionID": session.ID}
+		logger.Info("failed refresh token: session lifetime exceeded", args)
+		return Tokens{}, ErrorInvalidRefreshToken
+	}
+
+	return issueTokens(u, stored.SessionID, deadline)
+}
+
+// Logout revokes the access token of the principal and the session it belongs to.
+func Logout(p auth.Principal) error {
+	err := store.RevokeToken(p.TokenID, p.ExpiresAt)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": p.UserID}
+		logger.Error("failed revoke access token", args)
+		return err
+	}
+	verifiedTokens.invalidateToken(p.TokenID)
+
+	if p.SessionID != "" {
+		_, err = store.RevokeSession(p.UserID, p.SessionID)
+		if err != nil {
+			args := map[string]interface{}{"error": err.Error(), "userID": p.UserID}
+			logger.Error("failed revoke session", args)
+			return err
+		}
+		verifiedTokens.invalidateSession(p.SessionID)
+	}
+
+	return nil
+}
+
+// issueTokens issues a token pair of the session. Neither token outlives the
+// session deadline.
+func issueTokens(u store.User, sessionID string, deadline time.Time) (Tokens, error) {
+	var tokens Tokens
+	var err error
+
+	tokens.AccessExpiresAt = earliest(time.Now().Add(accessTokenLifetime), deadline)
+	tokens.AccessToken, err = generateToken(u, sessionID, tokens.AccessExpiresAt)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed generate token", args)
+		return Tokens{}, err
+	}
+
+	tokens.RefreshToken, err = newRandomToken(32)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed generate refresh token", args)
+		return Tokens{}, err
+	}
+
+	tokens.RefreshExpiresAt = earliest(time.Now().Add(refreshTokenLifetime), deadline)
+	err = store.CreateRefreshToken(store.RefreshToken{
+		UserID:    u.ID,
+		SessionID: sessionID,
+		TokenHash: hashToken(tokens.RefreshToken),
+		ExpiresAt: tokens.RefreshExpiresAt,
+	})
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed save refresh token", args)
+		return Tokens{}, err
+	}
+
+	return tokens, nil
+}
+
+func revokeReusedSession(token store.RefreshToken) error {
+	args := map[string]interface{}{"userID": token.UserID, "sessionID": token.SessionID}
+	logger.Security("refresh_token_reused", args)
+
+	err := store.RevokeRefreshTokenSession(token.SessionID)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.RevokeSession(token.UserID, token.SessionID)
+	if err != nil {
+		return err
+	}
+	verifiedTokens.invalidateSession(token.SessionID)
+
+	return ErrorRefreshTokenReused
+}
+
+func newRandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,88 @@
+package user
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenCacheTTL bounds how long another instance may keep accepting an access
+// token after the token, its session or all tokens of the user are revoked.
+// The instance which revokes drops the affected entries at once.
+const tokenCacheTTL = 5 * time.Second
+
+type tokenEntry struct {
+	userID    int
+	sessionID string
+	expiresAt time.Time
+}
+
+// tokenCache remembers access tokens which recently passed the token version,
+// revocation and session checks, so these checks do not hit the database on
+// every request. Entries are keyed by the token ID.
+type tokenCache struct {
+	mu        sync.Mutex
+	entries   map[string]tokenEntry
+	lastPrune time.Time
+}
+
+var verifiedTokens = tokenCache{entries: make(map[string]tokenEntry)}
+
+func (c *tokenCache) valid(tokenID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[tokenID]
+
+	return ok && time.Now().Before(entry.expiresAt)
+}
+
+func (c *tokenCache) add(tokenID string, userID int, sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	c.prune(now)
+	c.entries[tokenID] = tokenEntry{userID: userID, sessionID: sessionID, expiresAt: now.Add(tokenCacheTTL)}
+}
+
+func (c *tokenCache) invalidateToken(tokenID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, tokenID)
+}
+
+func (c *tokenCache) invalidateSession(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for tokenID, entry := range c.entries {
+		if entry.sessionID == sessionID {
+			delete(c.entries, tokenID)
+		}
+	}
+}
+
+func (c *tokenCache) invalidateUser(userID int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for tokenID, entry := range c.entries {
+		if entry.userID == userID {
+			delete(c.entries, tokenID)
+		}
+	}
+}
+
+func (c *tokenCache) prune(now time.Time) {
+	if now.Sub(c.lastPrune) < tokenCacheTTL {
+		return
+	}
+
+	for tokenID, entry := range c.entries {
+		if now.After(entry.expiresAt) {
+			delete(c.entries, tokenID)
+		}
+	}
+	c.lastPrune = now
+}
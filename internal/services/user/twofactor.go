@@ -0,0 +1,206 @@
+package user
+
+import (
+	"crypto/rand"
+	"strings"
+	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/keyring"
+	"github.com/MagicNetLab/go-diploma/internal/services/logger"
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
+	"github.com/golang-jwt/jwt/v4"
+)
+
+const (
+	recoveryCodeCount    = 10
+	mfaChallengeLifetime = 5 * time.Minute
+)
+
+// challengeClaims are carried by the short-lived token returned by the first
+// login step of users with two-factor authentication.
+type challengeClaims struct {
+	jwt.RegisteredClaims
+	UserID int
+}
+
+// EnrollTOTP generates a new TOTP secret for the user. The secret becomes
+// active only after ConfirmTOTP.
+func EnrollTOTP(userID int) (TOTPEnrollment, error) {
+	u, err := store.GetUserByID(userID)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	if u.TOTPEnabled {
+		return TOTPEnrollment{}, ErrorTOTPAlreadyEnabled
+	}
+
+	secret, err := newTOTPSecret()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed generate totp secret", args)
+		return TOTPEnrollment{}, err
+	}
+
+	err = store.SetUserTOTPSecret(u.ID, secret)
+	if err != nil {
+		return TOTPEnrollment{}, err
+	}
+
+	return TOTPEnrollment{Secret: secret, URI: totpURI(secret, u.Login)}, nil
+}
+
+// ConfirmTOTP enables two-factor authentication when the code matches the
+// pending secret, and returns one-time recovery codes.
+func ConfirmTOTP(userID int, code string) ([]string, error) {
+	u, err := store.GetUserByID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.TOTPEnabled {
+		return nil, ErrorTOTPAlreadyEnabled
+	}
+
+	if u.TOTPSecret == "" {
+		return nil, ErrorTOTPNotEnrolled
+	}
+
+	step, ok := matchTOTP(u.TOTPSecret, strings.TrimSpace(code), time.Now())
+	if !ok {
+		return nil, ErrorInvalidSecondFactor
+	}
+
+	if _, err = store.UseUserTOTPStep(u.ID, step); err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		c, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, c)
+		hashes = append(hashes, hashToken(normalizeRecoveryCode(c)))
+	}
+
+	err = store.EnableUserTOTP(u.ID, hashes)
+	if err != nil {
+		return nil, err
+	}
+
+	args := map[string]interface{}{"userID": u.ID}
+	logger.Security("totp_enabled", args)
+
+	return codes, nil
+}
+
+// CompleteLogin is the second login step: it checks the TOTP or recovery code
+// for the challenge returned by Login and issues the session tokens.
+func CompleteLogin(challenge string, code string, client Client) (Tokens, error) {
+	ip := client.IP
+	claims := &challengeClaims{}
+	token, err := jwt.ParseWithClaims(challenge, claims, keyring.Keyfunc)
+	if err != nil || !token.Valid || !claims.VerifyAudience(mfaAudience, true) || claims.UserID == 0 {
+		return Tokens{}, ErrorInvalidChallenge
+	}
+
+	revoked, err := store.IsTokenRevoked(claims.ID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if revoked {
+		return Tokens{}, ErrorInvalidChallenge
+	}
+
+	u, err := store.GetUserByID(claims.UserID)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if err = statusError(u.Status); err != nil {
+		return Tokens{}, err
+	}
+
+	login := NormalizeLogin(u.Login)
+	if err = throttle.check(login, ip); err != nil {
+		return Tokens{}, err
+	}
+
+	ok, err := verifySecondFactor(u, code)
+	if err != nil {
+		return Tokens{}, err
+	}
+
+	if !ok {
+		throttle.failure(login, ip)
+
+		args := map[string]interface{}{"userID": u.ID, "ip": ip}
+		logger.Error("failed user login: invalid second factor code", args)
+		return Tokens{}, ErrorInvalidSecondFactor
+	}
+
+	throttle.success(login)
+
+	if err = store.RevokeToken(claims.ID, claims.ExpiresAt.Time); err != nil {
+		return Tokens{}, err
+	}
+
+	return startSession(u, client)
+}
+
+func newChallenge(u store.User) error {
+	jti, err := newRandomToken(16)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(mfaChallengeLifetime)
+	challenge, err := keyring.Sign(challengeClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Audience:  jwt.ClaimStrings{mfaAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		UserID: u.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	return &SecondFactorRequiredError{Challenge: challenge, ExpiresAt: expiresAt}
+}
+
+func verifySecondFactor(u store.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if step, ok := matchTOTP(u.TOTPSecret, code, time.Now()); ok {
+		return store.UseUserTOTPStep(u.ID, step)
+	}
+
+	if len(code) == totpDigits {
+		return false, nil
+	}
+
+	return store.UseRecoveryCode(u.ID, hashToken(normalizeRecoveryCode(code)))
+}
+
+// newRecoveryCode returns a code like "k3xq-7m2p-wd9f".
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	c := strings.ToLower(totpEncoding.EncodeToString(b))[:12]
+
+	return c[0:4] + "-" + c[4:8] + "-" + c[8:12], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
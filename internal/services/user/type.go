@@ -2,7 +2,10 @@ package user
 
 import (
 	"errors"
+	"strings"
 	"time"
+
+	"github.com/MagicNetLab/go-diploma/internal/services/store"
 )
 
 type User struct {
@@ -12,5 +15,179 @@ type User struct {
 }
 
 var ErrorUserExists = errors.New("user already exists")
+var ErrorLoginThrottled = errors.New("too many failed login attempts")
+var ErrorResetThrottled = errors.New("too many password reset requests")
+var ErrorInvalidRefreshToken = errors.New("invalid refresh token")
+var ErrorRefreshTokenReused = errors.New("refresh token reused")
+var ErrorTokenRevoked = errors.New("token revoked")
+var ErrorInvalidResetToken = errors.New("invalid or expired password reset token")
+var ErrorTOTPAlreadyEnabled = errors.New("two-factor authentication already enabled")
+var ErrorTOTPNotEnrolled = errors.New("two-factor authentication is not enrolled")
+var ErrorInvalidSecondFactor = errors.New("invalid two-factor authentication code")
+var ErrorInvalidChallenge = errors.New("invalid or expired two-factor challenge")
+var ErrorSecondFactorRequired = errors.New("two-factor authentication required")
+var ErrorSessionNotFound = errors.New("session not found")
+var ErrorUserBlocked = errors.New("account is blocked")
+var ErrorUserDeleted = errors.New("account is deleted")
+var ErrorUserNotFound = errors.New("user not found")
+var ErrorInvalidRole = errors.New("invalid user role")
+var ErrorInvalidAPIKey = errors.New("invalid or expired api key")
+var ErrorAPIKeyNotFound = errors.New("api key not found")
+var ErrorInvalidOIDCState = errors.New("invalid or expired openid connect login state")
+
+// ValidationError describes a registration field which does not pass the
+// login or password policy.
+type ValidationError struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, v := range e {
+		messages = append(messages, v.Field+": "+v.Message)
+	}
+
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// ThrottledError is returned while the login or the client IP is delayed or
+// locked after failed attempts, or has requested too many password resets.
+// Reason is ErrorLoginThrottled unless set.
+type ThrottledError struct {
+	RetryAfter time.Duration
+	Reason     error
+}
+
+func (e *ThrottledError) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e *ThrottledError) Unwrap() error {
+	if e.Reason == nil {
+		return ErrorLoginThrottled
+	}
+
+	return e.Reason
+}
+
+// SecondFactorRequiredError is returned by Login when the password is correct
+// but the user has two-factor authentication enabled. The challenge must be
+// passed to CompleteLogin together with the code.
+type SecondFactorRequiredError struct {
+	Challenge string
+	ExpiresAt time.Time
+}
+
+func (e *SecondFactorRequiredError) Error() string {
+	return ErrorSecondFactorRequired.Error()
+}
+
+func (e *SecondFactorRequiredError) Unwrap() error {
+	return ErrorSecondFactorRequired
+}
+
+// AccountStatusError is returned for blocked and deleted accounts.
+type AccountStatusError struct {
+	Status string
+}
+
+func (e *AccountStatusError) Error() string {
+	return e.Unwrap().Error()
+}
 
-const tokenLifetime = 60 * time.Minute
+func (e *AccountStatusError) Unwrap() error {
+	if e.Status == store.UserStatusDeleted {
+		return ErrorUserDeleted
+	}
+
+	return ErrorUserBlocked
+}
+
+// Code is the machine readable error code returned to API clients.
+func (e *AccountStatusError) Code() string {
+	if e.Status == store.UserStatusDeleted {
+		return "account_deleted"
+	}
+
+	return "account_blocked"
+}
+
+const (
+	accessTokenLifetime  = 15 * time.Minute
+	refreshTokenLifetime = 30 * 24 * time.Hour
+
+	// accessAudience and mfaAudience keep login challenges from being
+	// accepted as access tokens, as both are signed with the same keys.
+	accessAudience = "access"
+	mfaAudience    = "mfa"
+
+	maxUserAgentLength = 512
+)
+
+// Tokens is a pair of a short-lived access JWT and an opaque refresh token
+// which can be exchanged for a new pair once.
+type Tokens struct {
+	AccessToken      string
+	AccessExpiresAt  time.Time
+	RefreshToken     string
+	RefreshExpiresAt time.Time
+}
+
+// TOTPEnrollment is a pending TOTP secret and its provisioning URI.
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
+
+// OIDCLogin is the start of a login through the external identity provider:
+// the user is redirected to URL, and StateToken is kept by the client until
+// the provider redirects back.
+type OIDCLogin struct {
+	URL        string
+	StateToken string
+	ExpiresAt  time.Time
+}
+
+// Client describes where a login request comes from. It is saved with the
+// session so users can recognise their devices.
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+type Session struct {
+	ID        string
+	IP        string
+	UserAgent string
+	CreatedAt time.Time
+	LastSeen  time.Time
+	Current   bool
+}
+
+// Account is the user profile shown to support staff.
+type Account struct {
+	ID          int
+	Login       string
+	Email       string
+	Role        string
+	Status      string
+	TOTPEnabled bool
+	// LoginConflict marks accounts whose login clashes with an older one when
+	// case is ignored; support should agree a new login with the owner.
+	LoginConflict bool
+}
+
+// APIKey describes an issued key. The key secret itself is never stored.
+type APIKey struct {
+	ID         int
+	Name       string
+	Prefix     string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  time.Time
+	LastUsedAt *time.Time
+}
@@ -2,23 +2,48 @@ package user
 
 import (
 	"errors"
-	"fmt"
-	"net/http"
+	"strings"
 	"time"
 
 	"github.com/MagicNetLab/go-diploma/internal/config"
+	"github.com/MagicNetLab/go-diploma/internal/services/auth"
+	"github.com/MagicNetLab/go-diploma/internal/services/keyring"
 	"github.com/MagicNetLab/go-diploma/internal/services/logger"
 	"github.com/MagicNetLab/go-diploma/internal/services/store"
 	"github.com/golang-jwt/jwt/v4"
-	"golang.org/x/crypto/bcrypt"
+	"github.com/jackc/pgx/v5"
 )
 
 type Claims struct {
 	jwt.RegisteredClaims
 	UserID int
+	// SessionID identifies the refresh token family issued at login.
+	SessionID string `json:"sid,omitempty"`
+	// TokenVersion must match the user token version, which is increased
+	// on password change to invalidate all issued tokens.
+	TokenVersion int    `json:"ver"`
+	Role         string `json:"role,omitempty"`
 }
 
-func Register(login string, password string) error {
+func Register(login string, password string, email string) error {
+	cnf, err := config.GetAppConfig()
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+		return err
+	}
+
+	displayed := displayLogin(login)
+	login = NormalizeLogin(login)
+	email = strings.TrimSpace(email)
+	validationErrors := append(validateLogin(login), validatePassword(cnf, password, login)...)
+	validationErrors = append(validationErrors, validateEmail(email)...)
+	if len(validationErrors) > 0 {
+		args := map[string]interface{}{"login": login, "error": validationErrors.Error()}
+		logger.Info("failed register user: validation failed", args)
+		return validationErrors
+	}
+
 	isLoginExists, err := store.HasUserByLogin(login)
 	if err != nil {
 		args := map[string]interface{}{"error": err.Error()}
@@ -39,7 +64,11 @@ func Register(login string, password string) error {
 		return err
 	}
 
-	err = store.CreateUser(login, hashPass)
+	err = store.CreateUser(displayed, login, hashPass, email)
+	if errors.Is(err, store.ErrorLoginNotUnique) {
+		return ErrorUserExists
+	}
+
 	if err != nil {
 		args := map[string]interface{}{"error": err.Error()}
 		logger.Error("failed register user", args)
@@ -49,129 +78,181 @@ func Register(login string, password string) error {
 	return nil
 }
 
-func Login(login string, password string) (string, error) {
-	u, err := store.GetUserByLogin(login)
+func Login(login string, password string, client Client) (Tokens, error) {
+	displayed := displayLogin(login)
+	login = NormalizeLogin(login)
+	ip := client.IP
+
+	if err := throttle.check(login, ip); err != nil {
+		args := map[string]interface{}{"login": login, "ip": ip}
+		logger.Error("failed user login: too many failed attempts", args)
+		return Tokens{}, err
+	}
+
+	u, err := store.GetUserByLogin(login, displayed)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			verifyDummyPassword(password)
+			throttle.failure(login, ip)
+		}
+
 		args := map[string]interface{}{"error": err.Error()}
 		logger.Error("failed get user by login to auth", args)
-		return "", err
+		return Tokens{}, err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
+	err = verifyPassword(u.Password, password)
 	if err != nil {
-		args := map[string]interface{}{"error": err.Error()}
+		throttle.failure(login, ip)
+
+		args := map[string]interface{}{"error": err.Error(), "login": login, "ip": ip}
 		logger.Error("failed user login: compare password is fail", args)
-		return "", err
+		return Tokens{}, err
 	}
 
-	tokenExpired := time.Now().Add(tokenLifetime)
-	token, err := generateToken(u.ID, tokenExpired)
-	if err != nil {
-		args := map[string]interface{}{"error": err.Error()}
-		logger.Error("failed generate token", args)
-		return "", err
+	if err = statusError(u.Status); err != nil {
+		args := map[string]interface{}{"userID": u.ID, "ip": ip, "status": u.Status}
+		logger.Security("inactive_user_login", args)
+		return Tokens{}, err
 	}
 
-	return token, nil
-}
+	rehashPassword(u, password)
 
-func CheckAuthorize(r *http.Request) bool {
-	cookie, err := r.Cookie("token")
-	if err != nil {
-		return false
+	if u.TOTPEnabled {
+		args := map[string]interface{}{"userID": u.ID}
+		logger.Info("user login: second factor required", args)
+		return Tokens{}, newChallenge(u)
 	}
 
-	appConfig, err := config.GetAppConfig()
+	throttle.success(login)
+
+	return startSession(u, client)
+}
+
+// SyncNormalizedLogins stores the NormalizeLogin form of logins beyond ASCII,
+// for which SQL lower() used by the migration does not case-fold the same
+// way. A user whose normalized login is taken by another account keeps the
+// login unchanged and is flagged for support, like the migration does.
+func SyncNormalizedLogins() error {
+	users, err := store.GetNonASCIILogins()
 	if err != nil {
-		args := map[string]interface{}{"error": err.Error()}
-		logger.Error("failed get app config", args)
-		return false
+		return err
 	}
 
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(cookie.Value, claims,
-		func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+	for _, u := range users {
+		_, err := store.SetNormalizedLogin(u.ID, NormalizeLogin(u.Login))
+		if errors.Is(err, store.ErrorLoginNotUnique) {
+			if _, err = store.FlagLoginConflict(u.ID); err != nil {
+				return err
 			}
 
-			return []byte(appConfig.GetJWTSecret()), nil
-		})
+			args := map[string]interface{}{"userID": u.ID, "login": u.Login}
+			logger.Security("login_normalization_conflict", args)
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// userRole returns the role of the user. Logins listed in the configured
+// admin logins are always admins, so the first admin can be bootstrapped
+// without editing the database.
+func userRole(u store.User) string {
+	cnf, err := config.GetAppConfig()
 	if err != nil {
 		args := map[string]interface{}{"error": err.Error()}
-		logger.Error("failed parse token", args)
-		return false
+		logger.Error("failed get app config", args)
+	} else {
+		for _, login := range cnf.GetAdminLogins() {
+			if NormalizeLogin(login) == NormalizeLogin(u.Login) {
+				return auth.RoleAdmin
+			}
+		}
 	}
 
-	if !token.Valid || claims.UserID == 0 {
-		return false
+	if !auth.IsValidRole(u.Role) {
+		return auth.RoleUser
 	}
 
-	return true
+	return u.Role
 }
 
-func GetAuthUserID(r *http.Request) (int, error) {
-	cookie, err := r.Cookie("token")
+func encodePassword(password string) (string, error) {
+	cnf, err := config.GetAppConfig()
 	if err != nil {
-		return 0, err
+		args := map[string]interface{}{"error": err.Error()}
+		logger.Error("failed get app config", args)
+		return "", err
 	}
 
-	appConfig, err := config.GetAppConfig()
+	hash, err := NewPasswordHasher(cnf).Hash(password)
 	if err != nil {
 		args := map[string]interface{}{"error": err.Error()}
-		logger.Error("failed get app config", args)
-		return 0, err
+		logger.Error("failed encode user password", args)
+		return "", err
 	}
 
-	claims := &Claims{}
-	token, err := jwt.ParseWithClaims(cookie.Value, claims,
-		func(t *jwt.Token) (interface{}, error) {
-			if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
-			}
+	return hash, nil
+}
 
-			return []byte(appConfig.GetJWTSecret()), nil
-		})
+// rehashPassword upgrades a hash produced by an outdated policy. The login
+// has already succeeded, so failures are only logged.
+func rehashPassword(u store.User, password string) {
+	cnf, err := config.GetAppConfig()
 	if err != nil {
 		args := map[string]interface{}{"error": err.Error()}
-		logger.Error("failed parse token", args)
-		return 0, err
+		logger.Error("failed get app config", args)
+		return
 	}
 
-	if !token.Valid || claims.UserID == 0 {
-		return 0, errors.New("invalid token or userID param")
+	hasher := NewPasswordHasher(cnf)
+	if !hasher.NeedsRehash(u.Password) {
+		return
 	}
 
-	return claims.UserID, nil
-}
+	hash, err := hasher.Hash(password)
+	if err != nil {
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed rehash user password", args)
+		return
+	}
 
-func encodePassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	err = store.UpdateUserPassword(u.ID, hash)
 	if err != nil {
-		args := map[string]interface{}{"error": err.Error()}
-		logger.Error("failed encode user password", args)
-		return "", err
+		args := map[string]interface{}{"error": err.Error(), "userID": u.ID}
+		logger.Error("failed save rehashed user password", args)
+		return
 	}
 
-	return string(hash), nil
+	args := map[string]interface{}{"userID": u.ID, "hasher": cnf.GetPasswordHasher()}
+	logger.Info("user password rehashed", args)
 }
 
-func generateToken(userID int, expired time.Time) (string, error) {
-	cnf, err := config.GetAppConfig()
+func generateToken(u store.User, sessionID string, expired time.Time) (string, error) {
+	jti, err := newRandomToken(16)
 	if err != nil {
 		args := map[string]interface{}{"error": err.Error()}
-		logger.Error("failed get app config", args)
+		logger.Error("failed generate token id", args)
 		return "", err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+	tokenString, err := keyring.Sign(Claims{
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Audience:  jwt.ClaimStrings{accessAudience},
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(expired),
 		},
-		UserID: userID,
+		UserID:       u.ID,
+		Role:         userRole(u),
+		SessionID:    sessionID,
+		TokenVersion: u.TokenVersion,
 	})
-
-	tokenString, err := token.SignedString([]byte(cnf.GetJWTSecret()))
 	if err != nil {
 		args := map[string]interface{}{"error": err.Error()}
 		logger.Error("failed generate token", args)
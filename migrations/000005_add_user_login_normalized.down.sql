@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS login_normalized_uniq_idx;
+
+ALTER TABLE users DROP COLUMN IF EXISTS login_conflict;
+ALTER TABLE users DROP COLUMN IF EXISTS login_normalized;
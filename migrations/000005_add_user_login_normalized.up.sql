@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS login_normalized VARCHAR;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS login_conflict BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Logins that differ only in case were accepted before. The oldest of such
+-- accounts gets the normalized login; the others keep their login unchanged,
+-- still log in with its exact spelling and are flagged for support to agree a
+-- new login with the owner.
+UPDATE users SET login_normalized = lower(login)
+WHERE id IN (SELECT min(id) FROM users GROUP BY lower(login));
+
+UPDATE users SET login_conflict = TRUE WHERE login_normalized IS NULL;
+
+-- lower() matches the application normalization for ASCII logins. Other logins
+-- are normalized by the application on start.
+CREATE UNIQUE INDEX IF NOT EXISTS login_normalized_uniq_idx ON users (login_normalized);
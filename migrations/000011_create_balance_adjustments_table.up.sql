@@ -0,0 +1,18 @@
+CREATE TABLE balance_adjustments
+(
+    id BIGSERIAL,
+    user_id BIGINT NOT NULL,
+    type VARCHAR NOT NULL,
+    amount FLOAT NOT NULL,
+    reason_code VARCHAR NOT NULL,
+    comment VARCHAR NOT NULL,
+    status VARCHAR NOT NULL,
+    created_by BIGINT NOT NULL,
+    decided_by BIGINT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    decided_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS balance_adjustments_id_idx ON balance_adjustments (id);
+CREATE INDEX IF NOT EXISTS balance_adjustments_user_idx ON balance_adjustments (user_id);
+CREATE INDEX IF NOT EXISTS balance_adjustments_status_idx ON balance_adjustments (status);
@@ -0,0 +1,12 @@
+CREATE TABLE user_identities
+(
+    id BIGSERIAL,
+    user_id BIGINT NOT NULL,
+    issuer VARCHAR NOT NULL,
+    subject VARCHAR NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS user_identities_id_idx ON user_identities (id);
+CREATE UNIQUE INDEX IF NOT EXISTS user_identities_subject_idx ON user_identities (issuer, subject);
+CREATE INDEX IF NOT EXISTS user_identities_user_idx ON user_identities (user_id);